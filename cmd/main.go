@@ -1,21 +1,39 @@
 package main
 
 import (
-	"fmt"
-)
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
-//TIP <p>To run your code, right-click the code and select <b>Run</b>.</p> <p>Alternatively, click
-// the <icon src="AllIcons.Actions.Execute"/> icon in the gutter and select the <b>Run</b> menu product from here.</p>
+	"github.com/DimKa163/stocks/internal/app"
+	"github.com/DimKa163/stocks/internal/cli"
+	"github.com/DimKa163/stocks/internal/config"
+)
 
 func main() {
-	//TIP <p>Press <shortcut actionId="ShowIntentionActions"/> when your caret is at the underlined text
-	// to see how GoLand suggests fixing the warning.</p><p>Alternatively, if available, click the lightbulb to view possible fixes.</p>
-	s := "gopher"
-	fmt.Printf("Hello and welcome, %s!\n", s)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	for i := 1; i <= 5; i++ {
-		//TIP <p>To start your debugging session, right-click your code in the editor and select the Debug option.</p> <p>We have set one <icon src="AllIcons.Debugger.Db_set_breakpoint"/> breakpoint
-		// for you, but you can always add more by pressing <shortcut actionId="ToggleLineBreakpoint"/>.</p>
-		fmt.Println("i =", 100/i)
+	if err := run(ctx, os.Args[1:]); err != nil {
+		slog.Error("stocks exited with error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run serves the APIs by default, other commands are one-off maintenance jobs.
+func run(ctx context.Context, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 && args[0] != "serve" {
+		return cli.Run(ctx, cfg, args[0], args[1:], os.Stdout)
+	}
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
 	}
+	return a.Run(ctx)
 }
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/application/ingest"
+	"github.com/DimKa163/stocks/internal/application/inventory"
+	"github.com/DimKa163/stocks/internal/application/movement"
+	"github.com/DimKa163/stocks/internal/application/outbox"
+	"github.com/DimKa163/stocks/internal/application/reservation"
+	"github.com/DimKa163/stocks/internal/config"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/infrastructure/cache"
+	"github.com/DimKa163/stocks/internal/infrastructure/notify"
+	"github.com/DimKa163/stocks/internal/infrastructure/persistance"
+	"github.com/DimKa163/stocks/internal/infrastructure/publisher"
+	"github.com/DimKa163/stocks/internal/transport/grpcapi"
+	"github.com/DimKa163/stocks/internal/transport/httpapi"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+)
+
+type App struct {
+	cfg                *config.Config
+	pool               *pgxpool.Pool
+	inventoryService   inventory.InventoryService
+	restInfoService    info.RestInfoService
+	reservationService reservation.ReservationService
+	movementService    movement.MovementService
+	ingestService      ingest.IngestService
+	sweeper            *reservation.Sweeper
+	relay              *outbox.Relay
+	publisher          *publisher.WriterPublisher
+	restCache          *cache.RestCache
+	listener           *notify.Listener
+	httpServer         *http.Server
+	cancelRequests     context.CancelFunc
+	grpcServer         *grpc.Server
+}
+
+func New(ctx context.Context, cfg *config.Config) (*App, error) {
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	inventoryLock, err := domain.ParseLockStrength(cfg.InventoryLock)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	inventoryOpts := []inventory.Option{
+		inventory.WithRestLock(domain.RowLock{Strength: inventoryLock, NoWait: cfg.RestLockNoWait}),
+	}
+	reservationOpts := make([]reservation.Option, 0, 1)
+	if cfg.RestLockNoWait {
+		reservationOpts = append(reservationOpts, reservation.WithNoWait())
+	}
+	pub, err := publisher.Open(cfg.OutboxPublisher)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	uow := persistance.NewUnitOfWork(pool, persistance.WithRetry(persistance.RetryPolicy{
+		MaxAttempts: cfg.TxMaxAttempts,
+		BaseDelay:   cfg.TxRetryDelay,
+		MaxDelay:    cfg.TxRetryMaxDelay,
+		Observe:     observeTxAttempts,
+	}))
+	listener := notify.NewListener(pool, time.Second)
+	notify.NewRestFeed(listener).Subscribe(logRestChange)
+	var (
+		restCache *cache.RestCache
+		infoOpts  []info.Option
+	)
+	if cfg.RestCacheSize > 0 {
+		restCache = cache.NewRestCache(uow.Rest(), cfg.RestCacheSize, cfg.RestCacheTTL)
+		listener.Handle(notify.RestChangedChannel, restCache.HandleNotification)
+		listener.OnSubscribe(restCache.Purge)
+		infoOpts = append(infoOpts, info.WithRestRepository(restCache))
+	}
+	a := &App{
+		cfg:                cfg,
+		pool:               pool,
+		inventoryService:   inventory.NewInventoryService(uow, inventoryOpts...),
+		restInfoService:    info.NewRestInfoService(uow, infoOpts...),
+		reservationService: reservation.NewReservationService(uow, cfg.ReservationTTL, reservationOpts...),
+		movementService:    movement.NewMovementService(uow),
+		ingestService:      ingest.NewIngestService(uow),
+		sweeper:            reservation.NewSweeper(uow, cfg.SweepInterval, cfg.SweepBatchSize),
+		relay:              outbox.NewRelay(uow, pub, cfg.OutboxInterval, cfg.OutboxBatchSize),
+		publisher:          pub,
+		restCache:          restCache,
+		listener:           listener,
+	}
+	// Requests are not cancelled by the shutdown signal, otherwise Shutdown
+	// would drain requests whose database calls already failed. They are only
+	// cancelled when they outlive the shutdown timeout.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	a.cancelRequests = cancelRequests
+	a.httpServer = &http.Server{
+		Addr:    cfg.HTTPAddr,
+		Handler: a.routes(),
+		BaseContext: func(net.Listener) context.Context {
+			return requestsCtx
+		},
+	}
+	a.grpcServer = grpc.NewServer()
+	grpcapi.NewServer(a.inventoryService, a.restInfoService).Register(a.grpcServer)
+	return a, nil
+}
+
+func (a *App) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", a.health)
+	mux.HandleFunc("GET /debug/rest-cache", a.restCacheStats)
+	httpapi.NewHandler(a.inventoryService, a.restInfoService, a.reservationService, a.movementService, a.ingestService).Register(mux)
+	return mux
+}
+
+func observeTxAttempts(attempts int, err error) {
+	if attempts > 1 {
+		slog.Warn("transaction retried", "attempts", attempts, "error", err)
+	}
+}
+
+func logRestChange(ctx context.Context, change *domain.RestChange) {
+	rest := change.Rest()
+	slog.DebugContext(ctx, "rest changed",
+		"op", change.Op,
+		"warehouse_id", rest.WarehouseID.String(),
+		"product_id", rest.ProductID.String(),
+		"delta", change.Delta().String())
+}
+
+func (a *App) health(w http.ResponseWriter, r *http.Request) {
+	if err := a.pool.Ping(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *App) restCacheStats(w http.ResponseWriter, _ *http.Request) {
+	if a.restCache == nil {
+		http.Error(w, "rest cache is disabled", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(a.restCache.Stats())
+}
+
+// Run serves until ctx is cancelled or one of the servers fails and then
+// drains in-flight requests within the configured shutdown timeout.
+func (a *App) Run(ctx context.Context) error {
+	defer a.pool.Close()
+	defer a.publisher.Close()
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	defer stopWorkers()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		a.sweeper.Run(workersCtx)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		a.listener.Run(workersCtx)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		a.relay.Run(workersCtx)
+	}()
+
+	errCh := make(chan error, 2)
+	go func() {
+		slog.Info("http server started", "addr", a.cfg.HTTPAddr)
+		if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+	lis, err := net.Listen("tcp", a.cfg.GRPCAddr)
+	if err != nil {
+		_ = a.httpServer.Close()
+		return err
+	}
+	go func() {
+		slog.Info("grpc server started", "addr", a.cfg.GRPCAddr)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			errCh <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case runErr = <-errCh:
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	if err := a.httpServer.Shutdown(shutdownCtx); err != nil {
+		runErr = errors.Join(runErr, err)
+	}
+	a.cancelRequests()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		a.grpcServer.Stop()
+	}
+	return runErr
+}
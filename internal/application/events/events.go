@@ -0,0 +1,105 @@
+// Package events builds the outbox events the application services write in
+// the transaction of the change they describe.
+package events
+
+import (
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	TopicReservationHeld      = "reservation.held"
+	TopicReservationConfirmed = "reservation.confirmed"
+	TopicReservationReleased  = "reservation.released"
+	TopicReservationExpired   = "reservation.expired"
+	TopicReservationFulfilled = "reservation.fulfilled"
+	TopicStockMoved           = "stock.moved"
+	TopicRestImported         = "rest.imported"
+)
+
+type Reservation struct {
+	ReservationID string    `json:"reservation_id"`
+	FilialID      string    `json:"filial_id"`
+	WarehouseID   string    `json:"warehouse_id"`
+	ProductID     string    `json:"product_id"`
+	Quantity      string    `json:"quantity"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+type ReservationEvent struct {
+	OrderID      string         `json:"order_id"`
+	Reservations []*Reservation `json:"reservations,omitempty"`
+}
+
+type Movement struct {
+	MovementID    string  `json:"movement_id"`
+	FilialID      *string `json:"filial_id,omitempty"`
+	WarehouseID   string  `json:"warehouse_id"`
+	ProductID     string  `json:"product_id"`
+	IntegrationID *string `json:"integration_id,omitempty"`
+	Delta         string  `json:"delta"`
+	Reason        string  `json:"reason"`
+}
+
+type MovementEvent struct {
+	DocumentID string      `json:"document_id"`
+	Movements  []*Movement `json:"movements"`
+}
+
+type ImportEvent struct {
+	DocumentID string `json:"document_id"`
+	Changed    int64  `json:"changed"`
+}
+
+// Reservations is an event about the reservations of an order, keyed by the
+// order. Without reservations only the order is reported.
+func Reservations(topic string, orderID guid.Guid, reservations []*domain.Reservation, now time.Time) (*domain.OutboxEvent, error) {
+	payload := ReservationEvent{OrderID: orderID.String()}
+	for _, reservation := range reservations {
+		payload.Reservations = append(payload.Reservations, &Reservation{
+			ReservationID: reservation.ReservationID.String(),
+			FilialID:      reservation.FilialID.String(),
+			WarehouseID:   reservation.WarehouseID.String(),
+			ProductID:     reservation.ProductID.String(),
+			Quantity:      reservation.Quantity.String(),
+			Status:        reservation.Status.String(),
+			ExpiresAt:     reservation.ExpiresAt,
+		})
+	}
+	return domain.NewOutboxEvent(topic, payload.OrderID, payload, now)
+}
+
+// Moved is a stock.moved event with the movements of one document, keyed by
+// the document.
+func Moved(documentID guid.Guid, movements []*domain.Movement, now time.Time) (*domain.OutboxEvent, error) {
+	payload := MovementEvent{DocumentID: documentID.String(), Movements: make([]*Movement, 0, len(movements))}
+	for _, movement := range movements {
+		m := &Movement{
+			MovementID:  movement.MovementID.String(),
+			WarehouseID: movement.WarehouseID.String(),
+			ProductID:   movement.ProductID.String(),
+			Delta:       movement.Delta.String(),
+			Reason:      movement.Reason.String(),
+		}
+		if movement.FilialID != nil {
+			filialID := movement.FilialID.String()
+			m.FilialID = &filialID
+		}
+		if movement.IntegrationID != nil {
+			integrationID := movement.IntegrationID.String()
+			m.IntegrationID = &integrationID
+		}
+		payload.Movements = append(payload.Movements, m)
+	}
+	return domain.NewOutboxEvent(TopicStockMoved, payload.DocumentID, payload, now)
+}
+
+// Imported is a rest.imported event for a bulk import that changed the given
+// number of rests. The movements themselves are in the ledger.
+func Imported(documentID guid.Guid, changed int64, now time.Time) (*domain.OutboxEvent, error) {
+	payload := ImportEvent{DocumentID: documentID.String(), Changed: changed}
+	return domain.NewOutboxEvent(TopicRestImported, payload.DocumentID, payload, now)
+}
@@ -2,6 +2,7 @@ package info
 
 import (
 	"context"
+	"errors"
 
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/beevik/guid"
@@ -14,46 +15,92 @@ type RestInfoService interface {
 }
 
 type RestInfoServiceImpl struct {
-	uow domain.UnitOfWork
+	uow   domain.UnitOfWork
+	rests domain.RestRepository
 }
 
-func NewRestInfoService(uow domain.UnitOfWork) *RestInfoServiceImpl {
-	return &RestInfoServiceImpl{uow: uow}
+type Option func(*RestInfoServiceImpl)
+
+// WithRestRepository reads rests from the repository instead of the unit of
+// work, e.g. from a cache.
+func WithRestRepository(rests domain.RestRepository) Option {
+	return func(r *RestInfoServiceImpl) {
+		r.rests = rests
+	}
+}
+
+func NewRestInfoService(uow domain.UnitOfWork, opts ...Option) *RestInfoServiceImpl {
+	r := &RestInfoServiceImpl{uow: uow}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *RestInfoServiceImpl) GetStockOneItemInfo(ctx context.Context, product RequestedProduct, filialID, shipmentID guid.Guid) (*OneStockInfo, error) {
-	restRepository := r.uow.Rest()
-	rest, err := restRepository.Get(ctx, product.ProductID, filialID, shipmentID)
-	if err != nil {
+	rest, err := r.restRepository().Get(ctx, filialID, shipmentID, product.ProductID)
+	if err != nil && !errors.Is(err, domain.ErrRestNotFound) {
 		return nil, err
 	}
-	if rest.Quantity.LessThan(product.Quantity) {
-		return &OneStockInfo{
-			InStock:     false,
-			ProductInfo: ProductInfo{ProductID: product.ProductID},
-		}, nil
-	}
-	return &OneStockInfo{
-		InStock:     true,
-		ProductInfo: ProductInfo{ProductID: product.ProductID, Rest: rest, Covered: true},
-	}, nil
+	return stockInfo(product, rest), nil
 }
 
+// GetStockManyItemsInfo reads the rests of all products in one round trip.
 func (r *RestInfoServiceImpl) GetStockManyItemsInfo(ctx context.Context, products []RequestedProduct, filialID, shipment guid.Guid) (*ManyStockInfo, error) {
+	productIDs := make([]guid.Guid, 0, len(products))
+	seen := make(map[guid.Guid]struct{}, len(products))
+	for _, product := range products {
+		if _, ok := seen[product.ProductID]; !ok {
+			seen[product.ProductID] = struct{}{}
+			productIDs = append(productIDs, product.ProductID)
+		}
+	}
+	rests, err := r.restRepository().GetMany(ctx, filialID, []guid.Guid{shipment}, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[guid.Guid]*domain.Rest, len(rests))
+	for _, rest := range rests {
+		byProduct[rest.ProductID] = rest
+	}
 	description := make([]ProductInfo, len(products))
 	allInStock := true
 	for i, product := range products {
-		stockInfo, err := r.GetStockOneItemInfo(ctx, product, filialID, shipment)
-		if err != nil {
-			return nil, err
-		}
-		if !stockInfo.InStock {
+		info := stockInfo(product, byProduct[product.ProductID])
+		if !info.InStock {
 			allInStock = false
 		}
-		description[i] = stockInfo.ProductInfo
+		description[i] = info.ProductInfo
 	}
 	return &ManyStockInfo{
 		InStock:     allInStock,
 		ProductInfo: description,
 	}, nil
 }
+
+func (r *RestInfoServiceImpl) restRepository() domain.RestRepository {
+	if r.rests != nil {
+		return r.rests
+	}
+	return r.uow.Rest()
+}
+
+// stockInfo reports whether the rest covers the product, a nil rest as zero stock.
+func stockInfo(product RequestedProduct, rest *domain.Rest) *OneStockInfo {
+	if rest == nil {
+		return &OneStockInfo{
+			InStock:     false,
+			ProductInfo: ProductInfo{ProductID: product.ProductID},
+		}
+	}
+	if rest.Available().LessThan(product.Quantity) {
+		return &OneStockInfo{
+			InStock:     false,
+			ProductInfo: ProductInfo{ProductID: product.ProductID, Rest: rest},
+		}
+	}
+	return &OneStockInfo{
+		InStock:     true,
+		ProductInfo: ProductInfo{ProductID: product.ProductID, Rest: rest, Covered: true},
+	}
+}
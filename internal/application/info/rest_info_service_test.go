@@ -0,0 +1,49 @@
+package info
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStockManyItemsInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	shipmentID := *guid.New()
+	inStockID := *guid.New()
+	shortID := *guid.New()
+	missingID := *guid.New()
+	inStock := &domain.Rest{RestID: *guid.New(), Quantity: decimal.NewFromInt(10), ProductID: inStockID, WarehouseID: shipmentID}
+	short := &domain.Rest{RestID: *guid.New(), Quantity: decimal.NewFromInt(1), ProductID: shortID, WarehouseID: shipmentID}
+	mockRep := mocks.NewMockRestRepository(ctrl)
+	mockRep.EXPECT().GetMany(ctx, filialID, []guid.Guid{shipmentID}, []guid.Guid{inStockID, shortID, missingID}).
+		Return([]*domain.Rest{short, inStock}, nil)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(mockRep).AnyTimes()
+	sut := NewRestInfoService(uow)
+
+	res, err := sut.GetStockManyItemsInfo(ctx, []RequestedProduct{
+		{ProductID: inStockID, Quantity: decimal.NewFromInt(3)},
+		{ProductID: shortID, Quantity: decimal.NewFromInt(3)},
+		{ProductID: missingID, Quantity: decimal.NewFromInt(3)},
+		{ProductID: inStockID, Quantity: decimal.NewFromInt(11)},
+	}, filialID, shipmentID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ManyStockInfo{
+		InStock: false,
+		ProductInfo: []ProductInfo{
+			{ProductID: inStockID, Rest: inStock, Covered: true},
+			{ProductID: shortID, Rest: short},
+			{ProductID: missingID},
+			{ProductID: inStockID, Rest: inStock},
+		},
+	}, res)
+}
@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+)
+
+// errDryRun rolls back the import transaction after the diff is computed.
+var errDryRun = errors.New("dry run")
+
+// ImportResult is the diff of a bulk import. Applied is false for a dry run.
+type ImportResult struct {
+	Staged  int64
+	Diff    []*domain.RestDiff
+	Applied bool
+}
+
+// Import bulk loads the rests through the staging table and merges them by
+// integration id. With dryRun it only reports what the merge would change.
+// The rests are streamed from the source open returns; open is called again
+// when the transaction is retried, so it must start over from the first rest.
+func (i *IngestServiceImpl) Import(ctx context.Context, documentID guid.Guid, open func() (domain.SnapshotRestSource, error), dryRun bool) (*ImportResult, error) {
+	var result *ImportResult
+	err := i.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		next, err := open()
+		if err != nil {
+			return err
+		}
+		staged, err := work.Staging().Stage(ctx, func() (*domain.SnapshotRest, error) {
+			rest, err := next()
+			if err == nil && rest != nil && rest.Quantity.IsNegative() {
+				return nil, ErrNegativeQuantity
+			}
+			return rest, err
+		})
+		if err != nil {
+			return err
+		}
+		diff, err := work.Staging().Diff(ctx)
+		if err != nil {
+			return err
+		}
+		result = &ImportResult{Staged: staged, Diff: diff}
+		if dryRun {
+			return errDryRun
+		}
+		now := i.now()
+		changed, err := work.Staging().Merge(ctx, documentID, now)
+		if err != nil {
+			return err
+		}
+		result.Applied = true
+		if changed == 0 {
+			return nil
+		}
+		event, err := events.Imported(documentID, changed, now)
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+)
+
+var ErrNegativeQuantity = errors.New("snapshot quantity must not be negative")
+
+// Stats counts what a snapshot did to the rests. Replaying the same snapshot
+// reports every rest as unchanged.
+type Stats struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+	Removed   int
+}
+
+type IngestService interface {
+	Ingest(ctx context.Context, snapshot *domain.RestSnapshot) (*Stats, error)
+
+	Import(ctx context.Context, documentID guid.Guid, open func() (domain.SnapshotRestSource, error), dryRun bool) (*ImportResult, error)
+}
+
+type IngestServiceImpl struct {
+	uow domain.UnitOfWork
+	now func() time.Time
+}
+
+func NewIngestService(uow domain.UnitOfWork) *IngestServiceImpl {
+	return &IngestServiceImpl{uow: uow, now: time.Now}
+}
+
+// Ingest applies the snapshot in one transaction and records every change of
+// a rest quantity in the ledger with the snapshot document id. A snapshot that
+// changed anything is announced with a stock.moved event.
+func (i *IngestServiceImpl) Ingest(ctx context.Context, snapshot *domain.RestSnapshot) (*Stats, error) {
+	keep := make([]guid.Guid, 0, len(snapshot.Rests))
+	seen := make(map[guid.Guid]struct{}, len(snapshot.Rests))
+	for _, rest := range snapshot.Rests {
+		if rest.Quantity.IsNegative() {
+			return nil, ErrNegativeQuantity
+		}
+		if _, ok := seen[rest.IntegrationID]; ok {
+			return nil, domain.ErrDuplicateIntegrationID
+		}
+		seen[rest.IntegrationID] = struct{}{}
+		keep = append(keep, rest.IntegrationID)
+	}
+	var stats *Stats
+	err := i.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		stats = &Stats{}
+		results := make([]*domain.SyncResult, 0, len(snapshot.Rests))
+		for _, rest := range snapshot.Rests {
+			result, err := work.Rest().Sync(ctx, rest)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		if snapshot.Full {
+			removed, err := work.Rest().ZeroMissing(ctx, snapshot.FilialID, keep)
+			if err != nil {
+				return err
+			}
+			results = append(results, removed...)
+		}
+		now := i.now()
+		movements := make([]*domain.Movement, 0)
+		for _, result := range results {
+			stats.add(result.Outcome)
+			if result.Rest == nil || result.Delta().IsZero() {
+				continue
+			}
+			movement := newSyncMovement(snapshot.DocumentID, result, now)
+			if err := work.Ledger().Append(ctx, movement); err != nil {
+				return err
+			}
+			movements = append(movements, movement)
+		}
+		if len(movements) == 0 {
+			return nil
+		}
+		event, err := events.Moved(snapshot.DocumentID, movements, now)
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *Stats) add(outcome domain.SyncOutcome) {
+	switch outcome {
+	case domain.SyncOutcomeINSERTED:
+		s.Inserted++
+	case domain.SyncOutcomeUPDATED:
+		s.Updated++
+	case domain.SyncOutcomeUNCHANGED:
+		s.Unchanged++
+	case domain.SyncOutcomeREMOVED:
+		s.Removed++
+	}
+}
+
+func newSyncMovement(documentID guid.Guid, result *domain.SyncResult, now time.Time) *domain.Movement {
+	return &domain.Movement{
+		MovementID:    *guid.New(),
+		FilialID:      result.Rest.FilialID,
+		WarehouseID:   result.Rest.WarehouseID,
+		ProductID:     result.Rest.ProductID,
+		IntegrationID: result.Rest.IntegrationID,
+		Delta:         result.Delta(),
+		Reason:        domain.MovementReasonSYNC,
+		DocumentID:    documentID,
+		CreatedAt:     now,
+	}
+}
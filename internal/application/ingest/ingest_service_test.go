@@ -0,0 +1,187 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUnitOfWork(ctrl *gomock.Controller, rests domain.RestRepository, ledger domain.LedgerRepository, outbox domain.OutboxRepository) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(rests).AnyTimes()
+	uow.EXPECT().Ledger().Return(ledger).AnyTimes()
+	uow.EXPECT().Outbox().Return(outbox).AnyTimes()
+	uow.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	}).AnyTimes()
+	return uow
+}
+
+func newSnapshotRest(quantity int64) *domain.SnapshotRest {
+	return &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		WarehouseID:   *guid.New(),
+		ProductID:     *guid.New(),
+		Quantity:      decimal.NewFromInt(quantity),
+	}
+}
+
+func TestIngestFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := guid.New()
+	inserted := newSnapshotRest(5)
+	unchanged := newSnapshotRest(3)
+	snapshot := &domain.RestSnapshot{
+		DocumentID: *guid.New(),
+		Full:       true,
+		FilialID:   filialID,
+		Rests:      []*domain.SnapshotRest{inserted, unchanged},
+	}
+	removedID := guid.New()
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Sync(ctx, inserted).Return(&domain.SyncResult{
+		Outcome: domain.SyncOutcomeINSERTED,
+		Rest:    &domain.Rest{IntegrationID: &inserted.IntegrationID, FilialID: filialID, Quantity: inserted.Quantity},
+	}, nil)
+	rests.EXPECT().Sync(ctx, unchanged).Return(&domain.SyncResult{Outcome: domain.SyncOutcomeUNCHANGED}, nil)
+	rests.EXPECT().ZeroMissing(ctx, filialID, []guid.Guid{inserted.IntegrationID, unchanged.IntegrationID}).Return([]*domain.SyncResult{{
+		Outcome:  domain.SyncOutcomeREMOVED,
+		Rest:     &domain.Rest{IntegrationID: removedID, FilialID: filialID, Quantity: decimal.Zero},
+		Previous: decimal.NewFromInt(7),
+	}}, nil)
+	ledger := mocks.NewMockLedgerRepository(ctrl)
+	var deltas []decimal.Decimal
+	ledger.EXPECT().Append(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, movement *domain.Movement) error {
+		assert.Equal(t, snapshot.DocumentID, movement.DocumentID)
+		assert.Equal(t, domain.MovementReasonSYNC, movement.Reason)
+		assert.Equal(t, filialID, movement.FilialID)
+		deltas = append(deltas, movement.Delta)
+		return nil
+	}).Times(2)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	var event *domain.OutboxEvent
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		event = e
+		return nil
+	})
+	sut := NewIngestService(newUnitOfWork(ctrl, rests, ledger, outbox))
+
+	stats, err := sut.Ingest(ctx, snapshot)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Inserted: 1, Unchanged: 1, Removed: 1}, stats)
+	assert.Equal(t, "5", deltas[0].String())
+	assert.Equal(t, "-7", deltas[1].String())
+	assert.Equal(t, events.TopicStockMoved, event.Topic)
+	assert.Equal(t, snapshot.DocumentID.String(), event.Key)
+}
+
+func TestIngestDeltaDoesNotRemove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	updated := newSnapshotRest(2)
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Sync(ctx, updated).Return(&domain.SyncResult{
+		Outcome:  domain.SyncOutcomeUPDATED,
+		Rest:     &domain.Rest{IntegrationID: &updated.IntegrationID, Quantity: updated.Quantity},
+		Previous: decimal.NewFromInt(6),
+	}, nil)
+	ledger := mocks.NewMockLedgerRepository(ctrl)
+	ledger.EXPECT().Append(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, movement *domain.Movement) error {
+		assert.Nil(t, movement.FilialID)
+		return nil
+	})
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	var event *domain.OutboxEvent
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		event = e
+		return nil
+	})
+	sut := NewIngestService(newUnitOfWork(ctrl, rests, ledger, outbox))
+
+	stats, err := sut.Ingest(ctx, &domain.RestSnapshot{DocumentID: *guid.New(), Rests: []*domain.SnapshotRest{updated}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Updated: 1}, stats)
+	assert.NotContains(t, string(event.Payload), "filial_id")
+}
+
+func TestIngestDuplicateIntegrationID(t *testing.T) {
+	rest := newSnapshotRest(1)
+	sut := NewIngestService(nil)
+
+	stats, err := sut.Ingest(context.Background(), &domain.RestSnapshot{Rests: []*domain.SnapshotRest{rest, rest}})
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateIntegrationID)
+	assert.Nil(t, stats)
+}
+
+func TestImportDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	rest := newSnapshotRest(4)
+	diff := []*domain.RestDiff{{IntegrationID: rest.IntegrationID, Quantity: rest.Quantity}}
+	staging := mocks.NewMockRestStagingRepository(ctrl)
+	var staged []*domain.SnapshotRest
+	staging.EXPECT().Stage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, next domain.SnapshotRestSource) (int64, error) {
+		for {
+			rest, err := next()
+			if err != nil || rest == nil {
+				return int64(len(staged)), err
+			}
+			staged = append(staged, rest)
+		}
+	})
+	staging.EXPECT().Diff(ctx).Return(diff, nil)
+	staging.EXPECT().Merge(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Staging().Return(staging).AnyTimes()
+	uow.EXPECT().Begin(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	})
+	sut := NewIngestService(uow)
+
+	result, err := sut.Import(ctx, *guid.New(), func() (domain.SnapshotRestSource, error) {
+		return domain.SnapshotRestsOf([]*domain.SnapshotRest{rest}), nil
+	}, true)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Applied)
+	assert.Equal(t, int64(1), result.Staged)
+	assert.Equal(t, diff, result.Diff)
+	assert.Equal(t, []*domain.SnapshotRest{rest}, staged)
+}
+
+func TestImportNegativeQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	staging := mocks.NewMockRestStagingRepository(ctrl)
+	staging.EXPECT().Stage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, next domain.SnapshotRestSource) (int64, error) {
+		for {
+			if rest, err := next(); err != nil || rest == nil {
+				return 0, err
+			}
+		}
+	})
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Staging().Return(staging).AnyTimes()
+	uow.EXPECT().Begin(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	})
+	sut := NewIngestService(uow)
+
+	result, err := sut.Import(ctx, *guid.New(), func() (domain.SnapshotRestSource, error) {
+		return domain.SnapshotRestsOf([]*domain.SnapshotRest{newSnapshotRest(1), newSnapshotRest(-1)}), nil
+	}, false)
+
+	assert.ErrorIs(t, err, ErrNegativeQuantity)
+	assert.Nil(t, result)
+}
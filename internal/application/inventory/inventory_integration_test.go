@@ -0,0 +1,53 @@
+//go:build integration
+
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/internal/infrastructure/persistance"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func TestInventoryEndToEnd(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	unavailableID := *guid.New()
+	shortID := *guid.New()
+	shippingID := *guid.New()
+	pgtest.SeedWarehouse(t, pool, &domain.Warehouse{WarehouseID: unavailableID, Type: domain.WarehouseTypeMAIN, RestAvailable: false})
+	pgtest.SeedWarehouse(t, pool, &domain.Warehouse{WarehouseID: shortID, Type: domain.WarehouseTypeMAIN, RestAvailable: true})
+	pgtest.SeedWarehouse(t, pool, &domain.Warehouse{WarehouseID: shippingID, Type: domain.WarehouseTypeMAINWH, RestAvailable: true})
+	pgtest.SeedRest(t, pool, &filialID, unavailableID, productID, decimal.NewFromInt(10))
+	pgtest.SeedRest(t, pool, &filialID, shortID, productID, decimal.NewFromInt(1))
+	pgtest.SeedRest(t, pool, &filialID, shippingID, productID, decimal.NewFromInt(3))
+	path := types.NewPathFromSlice([]guid.Guid{unavailableID, shortID, shippingID})
+	sut := NewInventoryService(persistance.NewUnitOfWork(pool),
+		WithRestLock(domain.RowLock{Strength: domain.LockForShare}))
+	items := []models.DeliveryItemer{models.NewSimpleProduct(productID, decimal.NewFromInt(2), false, models.Nearest, filialID)}
+
+	state, err := sut.Inventory(ctx, items, path, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.AllInStockAtOne, state.Result)
+	require.Len(t, state.StockStates, 1)
+	assert.Equal(t, &shippingID, state.StockStates[0].WarehouseID)
+	assert.Equal(t, "2", state.StockStates[0].Quantity.String())
+	assert.Equal(t, []*models.SkippedNode{
+		{WarehouseID: unavailableID, Reason: models.SkipReasonRestUnavailable},
+	}, state.SkippedNodes)
+}
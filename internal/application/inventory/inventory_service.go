@@ -2,6 +2,8 @@ package inventory
 
 import (
 	"context"
+	"errors"
+
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/DimKa163/stocks/internal/domain/models"
 	"github.com/DimKa163/stocks/internal/shared/types"
@@ -9,35 +11,107 @@ import (
 	"github.com/beevik/guid"
 )
 
+var ErrNoAvailableNodes = errors.New("no warehouse on the path can be shipped from")
+
 type InventoryService interface {
-	Inventory(ctx context.Context, domains []models.DeliveryItemer, path *types.Path) (*models.InventoryState, error)
+	Inventory(ctx context.Context, domains []models.DeliveryItemer, path *types.Path, pickup bool) (*models.InventoryState, error)
 }
 
 type InventoryServiceImpl struct {
-	uow domain.UnitOfWork
+	uow  domain.UnitOfWork
+	lock domain.RowLock
+}
+
+type Option func(*InventoryServiceImpl)
+
+// WithRestLock makes the allocation run in a transaction that locks the rest
+// rows it is based on until the plan is computed.
+func WithRestLock(lock domain.RowLock) Option {
+	return func(i *InventoryServiceImpl) {
+		i.lock = lock
+	}
 }
 
-func NewInventoryService(uow domain.UnitOfWork) *InventoryServiceImpl {
-	return &InventoryServiceImpl{uow: uow}
+func NewInventoryService(uow domain.UnitOfWork, opts ...Option) *InventoryServiceImpl {
+	i := &InventoryServiceImpl{uow: uow}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
 }
 
 func (i *InventoryServiceImpl) Inventory(
 	ctx context.Context,
 	domains []models.DeliveryItemer,
-	path *types.Path) (*models.InventoryState, error) {
-	stockStates := make([]*models.StockState, 0)
-	for _, d := range domains {
-		states, err := d.Find(ctx, i.uow.Rest(), path)
-		if err != nil {
-			return nil, err
-		}
-		stockStates = append(stockStates, states...)
+	path *types.Path,
+	pickup bool) (*models.InventoryState, error) {
+	if i.lock.Strength == domain.LockNone {
+		return i.inventory(ctx, i.uow, domains, path, pickup)
+	}
+	var state *models.InventoryState
+	err := i.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		var err error
+		state, err = i.inventory(ctx, work, domains, path, pickup)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return state, nil
+}
 
-	return withState(stockStates), nil
+func (i *InventoryServiceImpl) inventory(
+	ctx context.Context,
+	work domain.UnitOfWork,
+	domains []models.DeliveryItemer,
+	path *types.Path,
+	pickup bool) (*models.InventoryState, error) {
+	path, skipped, err := availablePath(ctx, work, path, pickup)
+	if err != nil {
+		return nil, err
+	}
+	stockStates, skippedNodes, err := models.FindOrder(ctx, work.Rest().WithLock(i.lock), path, domains)
+	if err != nil {
+		return nil, err
+	}
+	return withState(stockStates, append(skipped, skippedNodes...)), nil
 }
 
-func withState(stockStates []*models.StockState) *models.InventoryState {
+// availablePath drops the nodes logistics cannot ship this order from.
+// Warehouses without metadata are kept as is.
+func availablePath(ctx context.Context, work domain.UnitOfWork, path *types.Path, pickup bool) (*types.Path, []*models.SkippedNode, error) {
+	warehouses, err := work.Warehouse().GetMany(ctx, path.Nodes())
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[guid.Guid]*domain.Warehouse, len(warehouses))
+	for _, warehouse := range warehouses {
+		byID[warehouse.WarehouseID] = warehouse
+	}
+	available := types.NewPath(path.Len())
+	skipped := make([]*models.SkippedNode, 0)
+	for _, node := range path.Nodes() {
+		warehouse, ok := byID[node]
+		switch {
+		case ok && !warehouse.RestAvailable:
+			skipped = append(skipped, &models.SkippedNode{WarehouseID: node, Reason: models.SkipReasonRestUnavailable})
+		case ok && warehouse.PickupOnly && !pickup:
+			skipped = append(skipped, &models.SkippedNode{WarehouseID: node, Reason: models.SkipReasonPickupOnly})
+		default:
+			if cost, ok := path.LookupCost(node); ok {
+				available.AddNodeWithCost(node, cost)
+			} else {
+				available.AddNode(node)
+			}
+		}
+	}
+	if available.Len() == 0 {
+		return nil, nil, ErrNoAvailableNodes
+	}
+	return available, skipped, nil
+}
+
+func withState(stockStates []*models.StockState, skipped []*models.SkippedNode) *models.InventoryState {
 	var result models.InventoryResult
 	nodes := make(map[guid.Guid]int)
 	var toProduce int
@@ -64,5 +138,10 @@ func withState(stockStates []*models.StockState) *models.InventoryState {
 		result = models.AllToProduce
 	}
 
-	return &models.InventoryState{Result: result, StockStates: stockStates}
+	state := &models.InventoryState{Result: result, StockStates: stockStates, SkippedNodes: skipped}
+	for _, stockState := range stockStates {
+		state.Cost = state.Cost.Add(stockState.Cost)
+		state.LeadTime = max(state.LeadTime, stockState.LeadTime)
+	}
+	return state
 }
@@ -0,0 +1,198 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertStockStates compares costs by value, an unpriced state may carry
+// either representation of zero.
+func assertStockStates(t *testing.T, expected, actual []*models.StockState) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		exp, act := *expected[i], *actual[i]
+		assert.True(t, exp.Cost.Equal(act.Cost), "cost of state %d: expected %s, actual %s", i, exp.Cost, act.Cost)
+		exp.Cost, act.Cost = decimal.Zero, decimal.Zero
+		assert.Equal(t, exp, act)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	unavailableID := *guid.New()
+	pickupOnlyID := *guid.New()
+	shippingID := *guid.New()
+	path := types.NewPathFromSlice([]guid.Guid{unavailableID, pickupOnlyID, shippingID})
+	warehouseRep := mocks.NewMockWarehouseRepository(ctrl)
+	warehouseRep.EXPECT().GetMany(ctx, path.Nodes()).Return([]*domain.Warehouse{
+		{WarehouseID: unavailableID, Type: domain.WarehouseTypeMAIN, RestAvailable: false},
+		{WarehouseID: pickupOnlyID, Type: domain.WarehouseTypeSHOPPINGCENTER, RestAvailable: true, PickupOnly: true},
+	}, nil).Times(2)
+	restRep := mocks.NewMockRestRepository(ctrl)
+	restRep.EXPECT().WithLock(domain.RowLock{}).Return(restRep).Times(2)
+	restRep.EXPECT().GetMany(ctx, filialID, []guid.Guid{shippingID}, []guid.Guid{productID}).Return([]*domain.Rest{
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: shippingID},
+	}, nil)
+	restRep.EXPECT().GetMany(ctx, filialID, []guid.Guid{pickupOnlyID, shippingID}, []guid.Guid{productID}).Return([]*domain.Rest{
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: pickupOnlyID},
+	}, nil)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(restRep).AnyTimes()
+	uow.EXPECT().Warehouse().Return(warehouseRep).AnyTimes()
+	sut := NewInventoryService(uow)
+	items := []models.DeliveryItemer{models.NewSimpleProduct(productID, decimal.NewFromInt(2), false, models.Nearest, filialID)}
+
+	delivery, err := sut.Inventory(ctx, items, path, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AllInStockAtOne, delivery.Result)
+	assertStockStates(t, []*models.StockState{
+		{ProductID: productID, Quantity: decimal.NewFromInt(2), WarehouseID: &shippingID},
+	}, delivery.StockStates)
+	assert.Equal(t, []*models.SkippedNode{
+		{WarehouseID: unavailableID, Reason: models.SkipReasonRestUnavailable},
+		{WarehouseID: pickupOnlyID, Reason: models.SkipReasonPickupOnly},
+	}, delivery.SkippedNodes)
+	assert.True(t, delivery.Cost.IsZero())
+	assert.Zero(t, delivery.LeadTime)
+
+	pickup, err := sut.Inventory(ctx, items, path, true)
+
+	assert.NoError(t, err)
+	assertStockStates(t, []*models.StockState{
+		{ProductID: productID, Quantity: decimal.NewFromInt(2), WarehouseID: &pickupOnlyID},
+	}, pickup.StockStates)
+	assert.Equal(t, []*models.SkippedNode{
+		{WarehouseID: unavailableID, Reason: models.SkipReasonRestUnavailable},
+	}, pickup.SkippedNodes)
+}
+
+func TestInventoryNoAvailableNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	warehouseID := *guid.New()
+	path := types.NewPathFromSlice([]guid.Guid{warehouseID})
+	warehouseRep := mocks.NewMockWarehouseRepository(ctrl)
+	warehouseRep.EXPECT().GetMany(ctx, path.Nodes()).Return([]*domain.Warehouse{
+		{WarehouseID: warehouseID, RestAvailable: false},
+	}, nil)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Warehouse().Return(warehouseRep)
+	sut := NewInventoryService(uow)
+
+	_, err := sut.Inventory(ctx, nil, path, true)
+
+	assert.ErrorIs(t, err, ErrNoAvailableNodes)
+}
+
+func TestInventoryWithRestLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	warehouseID := *guid.New()
+	lock := domain.RowLock{Strength: domain.LockForUpdate, NoWait: true}
+	path := types.NewPathFromSlice([]guid.Guid{warehouseID})
+	warehouseRep := mocks.NewMockWarehouseRepository(ctrl)
+	warehouseRep.EXPECT().GetMany(ctx, path.Nodes()).Return(nil, nil)
+	lockedRep := mocks.NewMockRestRepository(ctrl)
+	lockedRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{productID}).Return(nil, domain.ErrLockNotAvailable)
+	restRep := mocks.NewMockRestRepository(ctrl)
+	restRep.EXPECT().WithLock(lock).Return(lockedRep)
+	tx := mocks.NewMockUnitOfWork(ctrl)
+	tx.EXPECT().Rest().Return(restRep)
+	tx.EXPECT().Warehouse().Return(warehouseRep)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Begin(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(tx)
+	})
+	sut := NewInventoryService(uow, WithRestLock(lock))
+	items := []models.DeliveryItemer{models.NewSimpleProduct(productID, decimal.NewFromInt(1), false, models.Nearest, filialID)}
+
+	_, err := sut.Inventory(ctx, items, path, false)
+
+	assert.ErrorIs(t, err, domain.ErrLockNotAvailable)
+}
+
+func TestInventoryCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	unavailableID := *guid.New()
+	nearID := *guid.New()
+	farID := *guid.New()
+	path := types.NewPath(3)
+	path.AddNodeWithCost(unavailableID, types.NodeCost{Cost: decimal.Zero})
+	path.AddNodeWithCost(nearID, types.NodeCost{Cost: decimal.NewFromInt(2), LeadTime: 24 * time.Hour})
+	path.AddNodeWithCost(farID, types.NodeCost{Cost: decimal.NewFromInt(1), LeadTime: 72 * time.Hour})
+	warehouseRep := mocks.NewMockWarehouseRepository(ctrl)
+	warehouseRep.EXPECT().GetMany(ctx, path.Nodes()).Return([]*domain.Warehouse{
+		{WarehouseID: unavailableID, RestAvailable: false},
+	}, nil)
+	restRep := mocks.NewMockRestRepository(ctrl)
+	restRep.EXPECT().WithLock(domain.RowLock{}).Return(restRep)
+	restRep.EXPECT().GetMany(ctx, filialID, []guid.Guid{nearID, farID}, []guid.Guid{productID}).Return([]*domain.Rest{
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(1), ProductID: productID, WarehouseID: nearID},
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: farID},
+	}, nil)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(restRep).AnyTimes()
+	uow.EXPECT().Warehouse().Return(warehouseRep).AnyTimes()
+	sut := NewInventoryService(uow)
+	items := []models.DeliveryItemer{models.NewSimpleProduct(productID, decimal.NewFromInt(3), false, models.Nearest, filialID)}
+
+	state, err := sut.Inventory(ctx, items, path, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AllInStockAtSeveral, state.Result)
+	assert.True(t, decimal.RequireFromString("4").Equal(state.Cost))
+	assert.Equal(t, 72*time.Hour, state.LeadTime)
+}
+
+func TestInventoryCheapestTakesUnpricedNodesLast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	unpricedID := *guid.New()
+	pricedID := *guid.New()
+	path := types.NewPath(2)
+	path.AddNode(unpricedID)
+	path.AddNodeWithCost(pricedID, types.NodeCost{Cost: decimal.NewFromInt(3)})
+	warehouseRep := mocks.NewMockWarehouseRepository(ctrl)
+	warehouseRep.EXPECT().GetMany(ctx, path.Nodes()).Return([]*domain.Warehouse{}, nil)
+	restRep := mocks.NewMockRestRepository(ctrl)
+	restRep.EXPECT().WithLock(domain.RowLock{}).Return(restRep)
+	restRep.EXPECT().GetMany(ctx, filialID, []guid.Guid{unpricedID, pricedID}, []guid.Guid{productID}).Return([]*domain.Rest{
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: unpricedID},
+		{RestID: *guid.New(), Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: pricedID},
+	}, nil)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(restRep).AnyTimes()
+	uow.EXPECT().Warehouse().Return(warehouseRep).AnyTimes()
+	sut := NewInventoryService(uow)
+	items := []models.DeliveryItemer{models.NewSimpleProduct(productID, decimal.NewFromInt(2), false, models.Cheapest, filialID)}
+
+	state, err := sut.Inventory(ctx, items, path, false)
+
+	require.NoError(t, err)
+	assertStockStates(t, []*models.StockState{
+		{ProductID: productID, Quantity: decimal.NewFromInt(2), WarehouseID: &pricedID, Cost: decimal.NewFromInt(6)},
+	}, state.StockStates)
+}
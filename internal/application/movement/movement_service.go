@@ -0,0 +1,179 @@
+package movement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("movement quantity must be positive")
+	ErrSameWarehouse   = errors.New("transfer source and destination are the same warehouse")
+	ErrInvalidReason   = errors.New("reason is not an outbound movement reason")
+)
+
+type Line struct {
+	ProductID guid.Guid
+	Quantity  decimal.Decimal
+}
+
+type MovementService interface {
+	Receive(ctx context.Context, documentID, filialID, warehouseID guid.Guid, lines []Line) ([]*domain.Movement, error)
+
+	// Ship takes stock out of the warehouse for a shipment or a write-off.
+	Ship(ctx context.Context, documentID, filialID, warehouseID guid.Guid, reason domain.MovementReason, lines []Line) ([]*domain.Movement, error)
+
+	// ShipOrder ships the confirmed reservations the order holds at the
+	// warehouse. The reservations are fulfilled in the transaction that takes
+	// the stock out, so they do not block their own shipment.
+	ShipOrder(ctx context.Context, documentID, orderID, filialID, warehouseID guid.Guid, lines []Line) ([]*domain.Movement, error)
+
+	Transfer(ctx context.Context, documentID, filialID, fromWarehouseID, toWarehouseID guid.Guid, lines []Line) ([]*domain.Movement, error)
+
+	// RestAsOf answers what the rest was at the given moment according to the ledger.
+	// A nil filialID addresses the rest without a filial.
+	RestAsOf(ctx context.Context, filialID *guid.Guid, warehouseID, productID guid.Guid, at time.Time) (*domain.Rest, error)
+}
+
+type MovementServiceImpl struct {
+	uow domain.UnitOfWork
+	now func() time.Time
+}
+
+func NewMovementService(uow domain.UnitOfWork) *MovementServiceImpl {
+	return &MovementServiceImpl{uow: uow, now: time.Now}
+}
+
+func (m *MovementServiceImpl) Receive(ctx context.Context, documentID, filialID, warehouseID guid.Guid, lines []Line) ([]*domain.Movement, error) {
+	if err := validate(lines); err != nil {
+		return nil, err
+	}
+	movements := make([]*domain.Movement, 0, len(lines))
+	for _, line := range lines {
+		movements = append(movements, m.newMovement(documentID, filialID, warehouseID, line.ProductID, line.Quantity, domain.MovementReasonRECEIPT))
+	}
+	return m.apply(ctx, documentID, movements, nil)
+}
+
+func (m *MovementServiceImpl) Ship(ctx context.Context, documentID, filialID, warehouseID guid.Guid, reason domain.MovementReason, lines []Line) ([]*domain.Movement, error) {
+	if reason != domain.MovementReasonSHIPMENT && reason != domain.MovementReasonWRITEOFF {
+		return nil, ErrInvalidReason
+	}
+	if err := validate(lines); err != nil {
+		return nil, err
+	}
+	movements := make([]*domain.Movement, 0, len(lines))
+	for _, line := range lines {
+		movements = append(movements, m.newMovement(documentID, filialID, warehouseID, line.ProductID, line.Quantity.Neg(), reason))
+	}
+	return m.apply(ctx, documentID, movements, nil)
+}
+
+func (m *MovementServiceImpl) ShipOrder(ctx context.Context, documentID, orderID, filialID, warehouseID guid.Guid, lines []Line) ([]*domain.Movement, error) {
+	if err := validate(lines); err != nil {
+		return nil, err
+	}
+	movements := make([]*domain.Movement, 0, len(lines))
+	for _, line := range lines {
+		movements = append(movements, m.newMovement(documentID, filialID, warehouseID, line.ProductID, line.Quantity.Neg(), domain.MovementReasonSHIPMENT))
+	}
+	return m.apply(ctx, documentID, movements, func(work domain.UnitOfWork) error {
+		if err := work.Reservation().LockOrder(ctx, orderID); err != nil {
+			return err
+		}
+		fulfilled, err := work.Reservation().Fulfil(ctx, orderID, warehouseID)
+		if err != nil {
+			return err
+		}
+		if len(fulfilled) == 0 {
+			return domain.ErrReservationNotFound
+		}
+		event, err := events.Reservations(events.TopicReservationFulfilled, orderID, fulfilled, m.now())
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+}
+
+func (m *MovementServiceImpl) Transfer(ctx context.Context, documentID, filialID, fromWarehouseID, toWarehouseID guid.Guid, lines []Line) ([]*domain.Movement, error) {
+	if fromWarehouseID == toWarehouseID {
+		return nil, ErrSameWarehouse
+	}
+	if err := validate(lines); err != nil {
+		return nil, err
+	}
+	movements := make([]*domain.Movement, 0, 2*len(lines))
+	for _, line := range lines {
+		movements = append(movements,
+			m.newMovement(documentID, filialID, fromWarehouseID, line.ProductID, line.Quantity.Neg(), domain.MovementReasonTRANSFEROUT),
+			m.newMovement(documentID, filialID, toWarehouseID, line.ProductID, line.Quantity, domain.MovementReasonTRANSFERIN))
+	}
+	return m.apply(ctx, documentID, movements, nil)
+}
+
+// apply changes the rests, records the movements and the stock.moved event in
+// one transaction, so a document is either applied completely or not at all.
+// prepare, if set, runs in the transaction before the rests are changed.
+func (m *MovementServiceImpl) apply(ctx context.Context, documentID guid.Guid, movements []*domain.Movement, prepare func(work domain.UnitOfWork) error) ([]*domain.Movement, error) {
+	err := m.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		if prepare != nil {
+			if err := prepare(work); err != nil {
+				return err
+			}
+		}
+		for _, movement := range movements {
+			rest, err := work.Rest().Adjust(ctx, *movement.FilialID, movement.WarehouseID, movement.ProductID, movement.Delta)
+			if err != nil {
+				return err
+			}
+			movement.IntegrationID = rest.IntegrationID
+			if err := work.Ledger().Append(ctx, movement); err != nil {
+				return err
+			}
+		}
+		event, err := events.Moved(documentID, movements, m.now())
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (m *MovementServiceImpl) RestAsOf(ctx context.Context, filialID *guid.Guid, warehouseID, productID guid.Guid, at time.Time) (*domain.Rest, error) {
+	return m.uow.Ledger().RestAsOf(ctx, filialID, warehouseID, productID, at)
+}
+
+func (m *MovementServiceImpl) newMovement(documentID, filialID, warehouseID, productID guid.Guid, delta decimal.Decimal, reason domain.MovementReason) *domain.Movement {
+	return &domain.Movement{
+		MovementID:  *guid.New(),
+		FilialID:    &filialID,
+		WarehouseID: warehouseID,
+		ProductID:   productID,
+		Delta:       delta,
+		Reason:      reason,
+		DocumentID:  documentID,
+		CreatedAt:   m.now(),
+	}
+}
+
+func validate(lines []Line) error {
+	if len(lines) == 0 {
+		return ErrInvalidQuantity
+	}
+	for _, line := range lines {
+		if !line.Quantity.IsPositive() {
+			return ErrInvalidQuantity
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package movement
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUnitOfWork(ctrl *gomock.Controller, rests domain.RestRepository, ledger domain.LedgerRepository, outbox domain.OutboxRepository) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Rest().Return(rests).AnyTimes()
+	uow.EXPECT().Ledger().Return(ledger).AnyTimes()
+	uow.EXPECT().Outbox().Return(outbox).AnyTimes()
+	uow.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	}).AnyTimes()
+	return uow
+}
+
+func TestTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	documentID := *guid.New()
+	filialID := *guid.New()
+	fromID := *guid.New()
+	toID := *guid.New()
+	productID := *guid.New()
+	integrationID := guid.New()
+	quantity := decimal.NewFromInt(4)
+	rests := mocks.NewMockRestRepository(ctrl)
+	ledger := mocks.NewMockLedgerRepository(ctrl)
+	gomock.InOrder(
+		rests.EXPECT().Adjust(ctx, filialID, fromID, productID, quantity.Neg()).Return(&domain.Rest{IntegrationID: integrationID}, nil),
+		ledger.EXPECT().Append(ctx, gomock.Any()).Return(nil),
+		rests.EXPECT().Adjust(ctx, filialID, toID, productID, quantity).Return(&domain.Rest{}, nil),
+		ledger.EXPECT().Append(ctx, gomock.Any()).Return(nil),
+	)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	var event *domain.OutboxEvent
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		event = e
+		return nil
+	})
+	sut := NewMovementService(newUnitOfWork(ctrl, rests, ledger, outbox))
+
+	movements, err := sut.Transfer(ctx, documentID, filialID, fromID, toID, []Line{{ProductID: productID, Quantity: quantity}})
+
+	assert.NoError(t, err)
+	assert.Len(t, movements, 2)
+	assert.Equal(t, domain.MovementReasonTRANSFEROUT, movements[0].Reason)
+	assert.Equal(t, fromID, movements[0].WarehouseID)
+	assert.Equal(t, integrationID, movements[0].IntegrationID)
+	assert.Equal(t, domain.MovementReasonTRANSFERIN, movements[1].Reason)
+	assert.Equal(t, toID, movements[1].WarehouseID)
+	for _, movement := range movements {
+		assert.Equal(t, documentID, movement.DocumentID)
+	}
+	assert.Equal(t, events.TopicStockMoved, event.Topic)
+	assert.Equal(t, documentID.String(), event.Key)
+	assert.Contains(t, string(event.Payload), `"reason":"TRANSFER_OUT"`)
+}
+
+func TestShipNegativeRest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Adjust(ctx, gomock.Any(), gomock.Any(), gomock.Any(), decimal.NewFromInt(-2)).Return(nil, domain.ErrNegativeRest)
+	sut := NewMovementService(newUnitOfWork(ctrl, rests, nil, nil))
+
+	movements, err := sut.Ship(ctx, *guid.New(), *guid.New(), *guid.New(), domain.MovementReasonWRITEOFF,
+		[]Line{{ProductID: *guid.New(), Quantity: decimal.NewFromInt(2)}})
+
+	assert.ErrorIs(t, err, domain.ErrNegativeRest)
+	assert.Nil(t, movements)
+}
+
+func TestShipOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	documentID := *guid.New()
+	orderID := *guid.New()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	quantity := decimal.NewFromInt(2)
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	rests := mocks.NewMockRestRepository(ctrl)
+	ledger := mocks.NewMockLedgerRepository(ctrl)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	var topics []string
+	gomock.InOrder(
+		reservations.EXPECT().LockOrder(ctx, orderID).Return(nil),
+		reservations.EXPECT().Fulfil(ctx, orderID, warehouseID).Return([]*domain.Reservation{
+			{ReservationID: *guid.New(), OrderID: orderID, FilialID: filialID, WarehouseID: warehouseID, ProductID: productID,
+				Quantity: quantity, Status: domain.ReservationStatusFULFILLED},
+		}, nil),
+		rests.EXPECT().Adjust(ctx, filialID, warehouseID, productID, quantity.Neg()).Return(&domain.Rest{}, nil),
+		ledger.EXPECT().Append(ctx, gomock.Any()).Return(nil),
+	)
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		topics = append(topics, e.Topic)
+		return nil
+	}).Times(2)
+	uow := newUnitOfWork(ctrl, rests, ledger, outbox)
+	uow.EXPECT().Reservation().Return(reservations).AnyTimes()
+	sut := NewMovementService(uow)
+
+	movements, err := sut.ShipOrder(ctx, documentID, orderID, filialID, warehouseID, []Line{{ProductID: productID, Quantity: quantity}})
+
+	assert.NoError(t, err)
+	assert.Len(t, movements, 1)
+	assert.Equal(t, domain.MovementReasonSHIPMENT, movements[0].Reason)
+	assert.Equal(t, []string{events.TopicReservationFulfilled, events.TopicStockMoved}, topics)
+}
+
+func TestShipOrderWithoutConfirmedReservations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	orderID := *guid.New()
+	warehouseID := *guid.New()
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	reservations.EXPECT().LockOrder(ctx, orderID).Return(nil)
+	reservations.EXPECT().Fulfil(ctx, orderID, warehouseID).Return([]*domain.Reservation{}, nil)
+	uow := newUnitOfWork(ctrl, nil, nil, nil)
+	uow.EXPECT().Reservation().Return(reservations).AnyTimes()
+	sut := NewMovementService(uow)
+
+	movements, err := sut.ShipOrder(ctx, *guid.New(), orderID, *guid.New(), warehouseID, []Line{{ProductID: *guid.New(), Quantity: decimal.NewFromInt(1)}})
+
+	assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+	assert.Nil(t, movements)
+}
+
+func TestValidation(t *testing.T) {
+	sut := NewMovementService(nil)
+	ctx := context.Background()
+	warehouseID := *guid.New()
+
+	_, err := sut.Receive(ctx, *guid.New(), *guid.New(), warehouseID, []Line{{ProductID: *guid.New(), Quantity: decimal.Zero}})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	_, err = sut.Ship(ctx, *guid.New(), *guid.New(), warehouseID, domain.MovementReasonRECEIPT, []Line{{ProductID: *guid.New(), Quantity: decimal.NewFromInt(1)}})
+	assert.ErrorIs(t, err, ErrInvalidReason)
+
+	_, err = sut.Transfer(ctx, *guid.New(), *guid.New(), warehouseID, warehouseID, []Line{{ProductID: *guid.New(), Quantity: decimal.NewFromInt(1)}})
+	assert.ErrorIs(t, err, ErrSameWarehouse)
+}
@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+)
+
+// Publisher delivers an event to its consumers. An event is published again
+// when marking it as published fails, so delivery is at least once.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// Relay drains the outbox to the publisher in position order.
+type Relay struct {
+	uow       domain.UnitOfWork
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+func NewRelay(uow domain.UnitOfWork, publisher Publisher, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		uow:       uow,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// Run drains the outbox every interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		published, err := r.Drain(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("outbox relay failed", "error", err)
+		}
+		if published > 0 {
+			slog.Debug("outbox events published", "events", published)
+		}
+	}
+}
+
+// Drain publishes pending events batch by batch until the outbox is empty and
+// returns how many were published. Each batch is published while its events
+// are locked and marked in the same transaction. Publishing stops at the first
+// failed event so later events never overtake it; the ones published before
+// it are still marked. A retried transaction publishes its batch again, which
+// at-least-once delivery allows.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		var (
+			fetched    int
+			published  []int64
+			publishErr error
+		)
+		err := r.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+			pending, err := work.Outbox().Pending(ctx, r.batchSize)
+			if err != nil {
+				return err
+			}
+			fetched = len(pending)
+			published, publishErr = r.publish(ctx, pending)
+			if len(published) == 0 {
+				return nil
+			}
+			return work.Outbox().MarkPublished(ctx, published, r.now())
+		})
+		if err != nil {
+			return total, err
+		}
+		total += len(published)
+		if publishErr != nil {
+			return total, publishErr
+		}
+		if fetched < r.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, events []*domain.OutboxEvent) ([]int64, error) {
+	published := make([]int64, 0, len(events))
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			return published, err
+		}
+		published = append(published, event.Position)
+	}
+	return published, nil
+}
@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type publisherFunc func(ctx context.Context, event *domain.OutboxEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+func newUnitOfWork(ctrl *gomock.Controller, outbox domain.OutboxRepository) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Outbox().Return(outbox).AnyTimes()
+	uow.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	}).AnyTimes()
+	return uow
+}
+
+func newEvents(positions ...int64) []*domain.OutboxEvent {
+	events := make([]*domain.OutboxEvent, 0, len(positions))
+	for _, position := range positions {
+		events = append(events, &domain.OutboxEvent{Position: position})
+	}
+	return events
+}
+
+func TestDrain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	gomock.InOrder(
+		outbox.EXPECT().Pending(ctx, 2).Return(newEvents(1, 2), nil),
+		outbox.EXPECT().MarkPublished(ctx, []int64{1, 2}, now).Return(nil),
+		outbox.EXPECT().Pending(ctx, 2).Return(newEvents(3), nil),
+		outbox.EXPECT().MarkPublished(ctx, []int64{3}, now).Return(nil),
+	)
+	var published []int64
+	sut := NewRelay(newUnitOfWork(ctrl, outbox), publisherFunc(func(_ context.Context, event *domain.OutboxEvent) error {
+		published = append(published, event.Position)
+		return nil
+	}), time.Second, 2)
+	sut.now = func() time.Time { return now }
+
+	n, err := sut.Drain(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int64{1, 2, 3}, published)
+}
+
+func TestDrainStopsAtFailedEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	unavailable := errors.New("broker unavailable")
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	outbox.EXPECT().Pending(ctx, 10).Return(newEvents(1, 2, 3), nil)
+	outbox.EXPECT().MarkPublished(ctx, []int64{1}, gomock.Any()).Return(nil)
+	attempted := 0
+	sut := NewRelay(newUnitOfWork(ctrl, outbox), publisherFunc(func(_ context.Context, event *domain.OutboxEvent) error {
+		attempted++
+		if event.Position == 2 {
+			return unavailable
+		}
+		return nil
+	}), time.Second, 10)
+
+	n, err := sut.Drain(ctx)
+
+	assert.ErrorIs(t, err, unavailable)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 2, attempted)
+}
+
+func TestDrainEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	outbox.EXPECT().Pending(ctx, 10).Return(nil, nil)
+	outbox.EXPECT().MarkPublished(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	sut := NewRelay(newUnitOfWork(ctrl, outbox), publisherFunc(func(context.Context, *domain.OutboxEvent) error {
+		t.Fatal("nothing to publish")
+		return nil
+	}), time.Second, 10)
+
+	n, err := sut.Drain(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,173 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/beevik/guid"
+)
+
+type ReservationService interface {
+	Reserve(ctx context.Context, orderID guid.Guid, filialID guid.Guid, state *models.InventoryState) ([]*domain.Reservation, error)
+
+	Confirm(ctx context.Context, orderID guid.Guid) error
+
+	Release(ctx context.Context, orderID guid.Guid) error
+}
+
+type ReservationServiceImpl struct {
+	uow  domain.UnitOfWork
+	ttl  time.Duration
+	lock domain.RowLock
+	now  func() time.Time
+}
+
+type Option func(*ReservationServiceImpl)
+
+// WithNoWait makes Reserve fail with domain.ErrLockNotAvailable instead of
+// waiting for rests locked by a concurrent reservation or stock movement.
+func WithNoWait() Option {
+	return func(r *ReservationServiceImpl) {
+		r.lock.NoWait = true
+	}
+}
+
+func NewReservationService(uow domain.UnitOfWork, ttl time.Duration, opts ...Option) *ReservationServiceImpl {
+	r := &ReservationServiceImpl{
+		uow:  uow,
+		ttl:  ttl,
+		lock: domain.RowLock{Strength: domain.LockForUpdate},
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Reserve holds every warehouse stock state of the plan for the order. Either
+// all of them are held or none is.
+func (r *ReservationServiceImpl) Reserve(ctx context.Context, orderID guid.Guid, filialID guid.Guid, state *models.InventoryState) ([]*domain.Reservation, error) {
+	now := r.now()
+	reservations := make([]*domain.Reservation, 0, len(state.StockStates))
+	warehouseIDs := make([]guid.Guid, 0, len(state.StockStates))
+	productIDs := make([]guid.Guid, 0, len(state.StockStates))
+	for _, stockState := range state.StockStates {
+		if stockState.Produce {
+			continue
+		}
+		warehouseIDs = append(warehouseIDs, *stockState.WarehouseID)
+		productIDs = append(productIDs, stockState.ProductID)
+		reservations = append(reservations, &domain.Reservation{
+			ReservationID: *guid.New(),
+			OrderID:       orderID,
+			FilialID:      filialID,
+			ProductID:     stockState.ProductID,
+			WarehouseID:   *stockState.WarehouseID,
+			Quantity:      stockState.Quantity,
+			Status:        domain.ReservationStatusACTIVE,
+			ExpiresAt:     now.Add(r.ttl),
+			CreatedAt:     now,
+		})
+	}
+	err := r.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		if err := work.Reservation().LockOrder(ctx, orderID); err != nil {
+			return err
+		}
+		existing, err := work.Reservation().GetByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, reservation := range existing {
+			if reservation.Status == domain.ReservationStatusCONFIRMED ||
+				reservation.Status == domain.ReservationStatusACTIVE && reservation.ExpiresAt.After(now) {
+				return domain.ErrAlreadyReserved
+			}
+		}
+		// Holds below must not interleave with other writers of the same rests.
+		if _, err = work.Rest().WithLock(r.lock).GetPairs(ctx, filialID, warehouseIDs, productIDs); err != nil {
+			return err
+		}
+		for _, reservation := range reservations {
+			if err = work.Reservation().Hold(ctx, reservation); err != nil {
+				return err
+			}
+		}
+		event, err := events.Reservations(events.TopicReservationHeld, orderID, reservations, now)
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *ReservationServiceImpl) Confirm(ctx context.Context, orderID guid.Guid) error {
+	return r.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		if err := work.Reservation().LockOrder(ctx, orderID); err != nil {
+			return err
+		}
+		existing, err := work.Reservation().GetByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		now := r.now()
+		active := make([]*domain.Reservation, 0, len(existing))
+		for _, reservation := range existing {
+			if reservation.Status != domain.ReservationStatusACTIVE {
+				continue
+			}
+			if !reservation.ExpiresAt.After(now) {
+				return domain.ErrReservationExpired
+			}
+			confirmed := *reservation
+			confirmed.Status = domain.ReservationStatusCONFIRMED
+			active = append(active, &confirmed)
+		}
+		if len(active) == 0 {
+			return domain.ErrReservationNotFound
+		}
+		confirmed, err := work.Reservation().SetStatus(ctx, orderID, domain.ReservationStatusACTIVE, domain.ReservationStatusCONFIRMED)
+		if err != nil {
+			return err
+		}
+		if confirmed != int64(len(active)) {
+			return domain.ErrReservationExpired
+		}
+		event, err := events.Reservations(events.TopicReservationConfirmed, orderID, active, now)
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+}
+
+func (r *ReservationServiceImpl) Release(ctx context.Context, orderID guid.Guid) error {
+	return r.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+		if err := work.Reservation().LockOrder(ctx, orderID); err != nil {
+			return err
+		}
+		released := int64(0)
+		for _, from := range []domain.ReservationStatus{domain.ReservationStatusACTIVE, domain.ReservationStatusCONFIRMED} {
+			n, err := work.Reservation().SetStatus(ctx, orderID, from, domain.ReservationStatusRELEASED)
+			if err != nil {
+				return err
+			}
+			released += n
+		}
+		if released == 0 {
+			return domain.ErrReservationNotFound
+		}
+		event, err := events.Reservations(events.TopicReservationReleased, orderID, nil, r.now())
+		if err != nil {
+			return err
+		}
+		return work.Outbox().Add(ctx, event)
+	})
+}
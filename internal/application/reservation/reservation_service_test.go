@@ -0,0 +1,211 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUnitOfWork(ctrl *gomock.Controller, reservations domain.ReservationRepository, rests domain.RestRepository, outbox domain.OutboxRepository) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Reservation().Return(reservations).AnyTimes()
+	uow.EXPECT().Rest().Return(rests).AnyTimes()
+	uow.EXPECT().Outbox().Return(outbox).AnyTimes()
+	uow.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(work domain.UnitOfWork) error, _ ...domain.TxOption) error {
+		return fn(uow)
+	}).AnyTimes()
+	return uow
+}
+
+func TestReserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	orderID := *guid.New()
+	filialID := *guid.New()
+	productID := *guid.New()
+	warehouseID1 := *guid.New()
+	warehouseID2 := *guid.New()
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	gomock.InOrder(
+		reservations.EXPECT().LockOrder(ctx, orderID).Return(nil),
+		reservations.EXPECT().GetByOrder(ctx, orderID).Return([]*domain.Reservation{
+			{OrderID: orderID, Status: domain.ReservationStatusACTIVE, ExpiresAt: now.Add(-time.Minute)},
+		}, nil),
+	)
+	held := make([]*domain.Reservation, 0)
+	reservations.EXPECT().Hold(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, r *domain.Reservation) error {
+		held = append(held, r)
+		return nil
+	}).Times(2)
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().WithLock(domain.RowLock{Strength: domain.LockForUpdate}).Return(rests)
+	rests.EXPECT().GetPairs(ctx, filialID, []guid.Guid{warehouseID1, warehouseID2}, []guid.Guid{productID, productID}).Return(nil, nil)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	var event *domain.OutboxEvent
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		event = e
+		return nil
+	})
+	sut := NewReservationService(newUnitOfWork(ctrl, reservations, rests, outbox), 15*time.Minute)
+	sut.now = func() time.Time { return now }
+
+	res, err := sut.Reserve(ctx, orderID, filialID, &models.InventoryState{
+		Result: models.PartiallyInStock,
+		StockStates: []*models.StockState{
+			{ProductID: productID, Quantity: decimal.NewFromInt(1), WarehouseID: &warehouseID1},
+			{ProductID: productID, Quantity: decimal.NewFromInt(2), WarehouseID: &warehouseID2},
+			{ProductID: productID, Quantity: decimal.NewFromInt(3), Produce: true},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, held, res)
+	assert.Len(t, res, 2)
+	for i, warehouseID := range []guid.Guid{warehouseID1, warehouseID2} {
+		assert.Equal(t, orderID, res[i].OrderID)
+		assert.Equal(t, filialID, res[i].FilialID)
+		assert.Equal(t, warehouseID, res[i].WarehouseID)
+		assert.Equal(t, domain.ReservationStatusACTIVE, res[i].Status)
+		assert.Equal(t, now.Add(15*time.Minute), res[i].ExpiresAt)
+	}
+	assert.Equal(t, events.TopicReservationHeld, event.Topic)
+	assert.Equal(t, orderID.String(), event.Key)
+	assert.Equal(t, now, event.CreatedAt)
+}
+
+func TestReserveInsufficientStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	warehouseID := *guid.New()
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	reservations.EXPECT().LockOrder(ctx, gomock.Any()).Return(nil)
+	reservations.EXPECT().GetByOrder(ctx, gomock.Any()).Return(nil, nil)
+	reservations.EXPECT().Hold(ctx, gomock.Any()).Return(domain.ErrInsufficientStock)
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().WithLock(domain.RowLock{Strength: domain.LockForUpdate, NoWait: true}).Return(rests)
+	rests.EXPECT().GetPairs(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	sut := NewReservationService(newUnitOfWork(ctrl, reservations, rests, nil), time.Minute, WithNoWait())
+
+	res, err := sut.Reserve(ctx, *guid.New(), *guid.New(), &models.InventoryState{
+		StockStates: []*models.StockState{
+			{ProductID: *guid.New(), Quantity: decimal.NewFromInt(1), WarehouseID: &warehouseID},
+		},
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Nil(t, res)
+}
+
+func TestConfirm(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	orderID := *guid.New()
+	cases := []struct {
+		Name     string
+		Existing []*domain.Reservation
+		Updated  int64
+		Err      error
+	}{
+		{
+			Name:     "Active reservations should be confirmed",
+			Existing: []*domain.Reservation{{Status: domain.ReservationStatusACTIVE, ExpiresAt: now.Add(time.Minute)}},
+			Updated:  1,
+		},
+		{
+			Name: "Expired reservations should not be confirmed",
+			Existing: []*domain.Reservation{
+				{Status: domain.ReservationStatusACTIVE, ExpiresAt: now.Add(time.Minute)},
+				{Status: domain.ReservationStatusACTIVE, ExpiresAt: now.Add(-time.Minute)},
+			},
+			Err: domain.ErrReservationExpired,
+		},
+		{
+			Name:     "Released reservations should not be confirmed",
+			Existing: []*domain.Reservation{{Status: domain.ReservationStatusRELEASED, ExpiresAt: now.Add(time.Minute)}},
+			Err:      domain.ErrReservationNotFound,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			reservations := mocks.NewMockReservationRepository(ctrl)
+			gomock.InOrder(
+				reservations.EXPECT().LockOrder(ctx, orderID).Return(nil),
+				reservations.EXPECT().GetByOrder(ctx, orderID).Return(tt.Existing, nil),
+			)
+			outbox := mocks.NewMockOutboxRepository(ctrl)
+			if tt.Err == nil {
+				reservations.EXPECT().SetStatus(ctx, orderID, domain.ReservationStatusACTIVE, domain.ReservationStatusCONFIRMED).Return(tt.Updated, nil)
+				outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+					assert.Equal(t, events.TopicReservationConfirmed, e.Topic)
+					assert.Contains(t, string(e.Payload), `"status":"CONFIRMED"`)
+					return nil
+				})
+			}
+			sut := NewReservationService(newUnitOfWork(ctrl, reservations, nil, outbox), time.Minute)
+			sut.now = func() time.Time { return now }
+
+			err := sut.Confirm(ctx, orderID)
+
+			assert.ErrorIs(t, err, tt.Err)
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	orderID := *guid.New()
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	gomock.InOrder(
+		reservations.EXPECT().LockOrder(ctx, orderID).Return(nil),
+		reservations.EXPECT().SetStatus(ctx, orderID, domain.ReservationStatusACTIVE, domain.ReservationStatusRELEASED).Return(int64(0), nil),
+		reservations.EXPECT().SetStatus(ctx, orderID, domain.ReservationStatusCONFIRMED, domain.ReservationStatusRELEASED).Return(int64(0), nil),
+	)
+	sut := NewReservationService(newUnitOfWork(ctrl, reservations, nil, nil), time.Minute)
+
+	err := sut.Release(ctx, orderID)
+
+	assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+}
+
+func TestSweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	warehouseID1 := *guid.New()
+	warehouseID2 := *guid.New()
+	reservations := mocks.NewMockReservationRepository(ctrl)
+	gomock.InOrder(
+		reservations.EXPECT().ExpireBatch(ctx, 2).Return([]*domain.Reservation{
+			{WarehouseID: warehouseID1, Quantity: decimal.NewFromInt(1)},
+			{WarehouseID: warehouseID2, Quantity: decimal.NewFromInt(2)},
+		}, nil),
+		reservations.EXPECT().ExpireBatch(ctx, 2).Return([]*domain.Reservation{
+			{WarehouseID: warehouseID1, Quantity: decimal.NewFromInt(3)},
+		}, nil),
+	)
+	outbox := mocks.NewMockOutboxRepository(ctrl)
+	outbox.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.OutboxEvent) error {
+		assert.Equal(t, events.TopicReservationExpired, e.Topic)
+		return nil
+	}).Times(2)
+	sut := NewSweeper(newUnitOfWork(ctrl, reservations, nil, outbox), time.Minute, 2)
+
+	released, err := sut.Sweep(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[guid.Guid]decimal.Decimal{
+		warehouseID1: decimal.NewFromInt(4),
+		warehouseID2: decimal.NewFromInt(2),
+	}, released)
+}
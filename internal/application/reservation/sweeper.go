@@ -0,0 +1,94 @@
+package reservation
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/events"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+// Sweeper expires active reservations that were never confirmed so they stop
+// holding stock.
+type Sweeper struct {
+	uow       domain.UnitOfWork
+	interval  time.Duration
+	batchSize int
+}
+
+func NewSweeper(uow domain.UnitOfWork, interval time.Duration, batchSize int) *Sweeper {
+	return &Sweeper{uow: uow, interval: interval, batchSize: batchSize}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		released, err := s.Sweep(ctx)
+		if err != nil {
+			slog.Error("reservation sweep failed", "error", err)
+		}
+		for warehouseID, units := range released {
+			slog.Info("expired reservations released", "warehouse_id", warehouseID.String(), "units", units.String())
+		}
+	}
+}
+
+// Sweep expires stale reservations batch by batch, each batch in its own
+// transaction, and returns the released units per warehouse.
+func (s *Sweeper) Sweep(ctx context.Context) (map[guid.Guid]decimal.Decimal, error) {
+	released := make(map[guid.Guid]decimal.Decimal)
+	for {
+		var expired []*domain.Reservation
+		err := s.uow.Begin(ctx, func(work domain.UnitOfWork) error {
+			var err error
+			expired, err = work.Reservation().ExpireBatch(ctx, s.batchSize)
+			if err != nil {
+				return err
+			}
+			return addExpiredEvents(ctx, work, expired)
+		})
+		if err != nil {
+			return released, err
+		}
+		for _, reservation := range expired {
+			released[reservation.WarehouseID] = released[reservation.WarehouseID].Add(reservation.Quantity)
+		}
+		if len(expired) < s.batchSize {
+			return released, nil
+		}
+	}
+}
+
+// addExpiredEvents writes one reservation.expired event per order in the
+// order the batch returned them.
+func addExpiredEvents(ctx context.Context, work domain.UnitOfWork, expired []*domain.Reservation) error {
+	orderIDs := make([]guid.Guid, 0)
+	byOrder := make(map[guid.Guid][]*domain.Reservation)
+	for _, reservation := range expired {
+		if _, ok := byOrder[reservation.OrderID]; !ok {
+			orderIDs = append(orderIDs, reservation.OrderID)
+		}
+		byOrder[reservation.OrderID] = append(byOrder[reservation.OrderID], reservation)
+	}
+	now := time.Now()
+	for _, orderID := range orderIDs {
+		event, err := events.Reservations(events.TopicReservationExpired, orderID, byOrder[orderID], now)
+		if err != nil {
+			return err
+		}
+		if err = work.Outbox().Add(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/DimKa163/stocks/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run executes one of the maintenance commands against the configured database.
+func Run(ctx context.Context, cfg *config.Config, command string, args []string, stdout io.Writer) error {
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	switch command {
+	case "import":
+		return runImport(ctx, pool, args, stdout)
+	case "export":
+		return runExport(ctx, pool, args, stdout)
+	case "migrate":
+		return runMigrate(ctx, pool, args, stdout)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
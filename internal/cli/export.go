@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/infrastructure/persistance"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func runExport(ctx context.Context, pool *pgxpool.Pool, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", formatCSV, "output format, csv or jsonl")
+	output := flags.String("output", "", "output file (default: stdout)")
+	filial := flags.String("filial", "", "export only rests of the filial")
+	warehouses := flags.String("warehouses", "", "comma separated warehouse ids")
+	products := flags.String("products", "", "comma separated product ids")
+	nonZero := flags.Bool("non-zero", false, "skip rests with zero quantity")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: export [-format csv|jsonl] [-output FILE] [-filial guid] [-warehouses guid,..] [-products guid,..] [-non-zero]")
+	}
+	filter := domain.RestFilter{NonZero: *nonZero}
+	if *filial != "" {
+		filialID, err := parseGuid("filial", *filial)
+		if err != nil {
+			return err
+		}
+		filter.FilialID = &filialID
+	}
+	var err error
+	if filter.WarehouseIDs, err = parseGuidList("warehouses", *warehouses); err != nil {
+		return err
+	}
+	if filter.ProductIDs, err = parseGuidList("products", *products); err != nil {
+		return err
+	}
+	w := stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return exportRests(ctx, persistance.NewRestRepository(pool), filter, *format, w)
+}
+
+// exportRests writes the rests in the column order of restColumns as they
+// are read, so memory does not grow with the number of rests. Import merges
+// by integration id, so rests without one are left out.
+func exportRests(ctx context.Context, rests domain.RestRepository, filter domain.RestFilter, format string, w io.Writer) error {
+	var write func(record restRecord) error
+	var flush func() error
+	switch format {
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(restColumns); err != nil {
+			return err
+		}
+		write = func(record restRecord) error {
+			return writer.Write([]string{record.FilialID, record.WarehouseID, record.ProductID, record.Quantity.String(), record.IntegrationID})
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case formatJSONL:
+		buffered := bufio.NewWriter(w)
+		encoder := json.NewEncoder(buffered)
+		write = func(record restRecord) error {
+			return encoder.Encode(record)
+		}
+		flush = buffered.Flush
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	err := rests.Each(ctx, filter, func(rest *domain.Rest) error {
+		if rest.IntegrationID == nil {
+			return nil
+		}
+		return write(restRecord{
+			FilialID:      optionalGuid(rest.FilialID),
+			WarehouseID:   rest.WarehouseID.String(),
+			ProductID:     rest.ProductID.String(),
+			Quantity:      rest.Quantity,
+			IntegrationID: optionalGuid(rest.IntegrationID),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+func parseGuidList(field, s string) ([]guid.Guid, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	ids := make([]guid.Guid, len(parts))
+	for i, part := range parts {
+		id, err := parseGuid(field, part)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func optionalGuid(g *guid.Guid) string {
+	if g == nil {
+		return ""
+	}
+	return g.String()
+}
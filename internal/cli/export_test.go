@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filter := domain.RestFilter{FilialID: guid.New(), NonZero: true}
+	exported := []*domain.Rest{
+		{FilialID: filter.FilialID, IntegrationID: guid.New(), WarehouseID: *guid.New(), ProductID: *guid.New(), Quantity: decimal.RequireFromString("7.125")},
+		{IntegrationID: guid.New(), WarehouseID: *guid.New(), ProductID: *guid.New(), Quantity: decimal.NewFromInt(2)},
+	}
+	local := &domain.Rest{FilialID: filter.FilialID, WarehouseID: *guid.New(), ProductID: *guid.New(), Quantity: decimal.NewFromInt(3)}
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Each(ctx, filter, gomock.Any()).DoAndReturn(func(_ context.Context, _ domain.RestFilter, fn func(*domain.Rest) error) error {
+		for _, rest := range append(exported, local) {
+			if err := fn(rest); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Times(2)
+
+	for _, format := range []string{formatCSV, formatJSONL} {
+		var out bytes.Buffer
+		require.NoError(t, exportRests(ctx, rests, filter, format, &out))
+
+		imported, err := readAll(&out, format)
+
+		require.NoError(t, err, format)
+		require.Len(t, imported, len(exported), format)
+		for i, rest := range exported {
+			assert.Equal(t, *rest.IntegrationID, imported[i].IntegrationID, format)
+			assert.Equal(t, rest.FilialID, imported[i].FilialID, format)
+			assert.Equal(t, rest.WarehouseID, imported[i].WarehouseID, format)
+			assert.Equal(t, rest.ProductID, imported[i].ProductID, format)
+			assert.True(t, rest.Quantity.Equal(imported[i].Quantity), format)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DimKa163/stocks/internal/application/ingest"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/infrastructure/persistance"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+// restColumns is the column order of CSV files, shared by import and export.
+var restColumns = []string{"filial_id", "warehouse_id", "product_id", "quantity", "integration_id"}
+
+type restRecord struct {
+	FilialID      string          `json:"filial_id"`
+	WarehouseID   string          `json:"warehouse_id"`
+	ProductID     string          `json:"product_id"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	IntegrationID string          `json:"integration_id"`
+}
+
+func runImport(ctx context.Context, pool *pgxpool.Pool, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "input format, csv or jsonl (default: by file extension)")
+	dryRun := flags.Bool("dry-run", false, "print the diff without changing the rests")
+	documentID := flags.String("document-id", "", "ledger document id of the import (default: random)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-format csv|jsonl] [-dry-run] [-document-id guid] FILE")
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	document := guid.New()
+	if *documentID != "" {
+		var err error
+		if document, err = guid.ParseString(*documentID); err != nil {
+			return fmt.Errorf("document-id: %w", err)
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// The import reopens the source when its transaction is retried.
+	open := func() (domain.SnapshotRestSource, error) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return readRests(file, *format)
+	}
+	service := ingest.NewIngestService(persistance.NewUnitOfWork(pool))
+	result, err := service.Import(ctx, *document, open, *dryRun)
+	if err != nil {
+		return err
+	}
+	return printDiff(stdout, result)
+}
+
+// readRests returns a source reading the rests one record at a time, so
+// memory does not grow with the size of the file.
+func readRests(r io.Reader, format string) (domain.SnapshotRestSource, error) {
+	switch format {
+	case formatCSV:
+		return readCSV(r)
+	case formatJSONL:
+		return readJSONL(r), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// readCSV expects a header row naming the columns in any order.
+func readCSV(r io.Reader) (domain.SnapshotRestSource, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.TrimSpace(column)] = i
+	}
+	for _, column := range restColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("header: missing column %s", column)
+		}
+	}
+	line := 1
+	return func() (*domain.SnapshotRest, error) {
+		line++
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		quantity, err := decimal.NewFromString(row[index["quantity"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: quantity: %w", line, err)
+		}
+		rest, err := restRecord{
+			FilialID:      row[index["filial_id"]],
+			WarehouseID:   row[index["warehouse_id"]],
+			ProductID:     row[index["product_id"]],
+			Quantity:      quantity,
+			IntegrationID: row[index["integration_id"]],
+		}.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		return rest, nil
+	}, nil
+}
+
+func readJSONL(r io.Reader) domain.SnapshotRestSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	return func() (*domain.SnapshotRest, error) {
+		for scanner.Scan() {
+			line++
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			var record restRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rest, err := record.toModel()
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			return rest, nil
+		}
+		return nil, scanner.Err()
+	}
+}
+
+func (r restRecord) toModel() (*domain.SnapshotRest, error) {
+	integrationID, err := parseGuid("integration_id", r.IntegrationID)
+	if err != nil {
+		return nil, err
+	}
+	warehouseID, err := parseGuid("warehouse_id", r.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := parseGuid("product_id", r.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Quantity.IsNegative() {
+		return nil, fmt.Errorf("quantity: %w", ingest.ErrNegativeQuantity)
+	}
+	rest := &domain.SnapshotRest{
+		IntegrationID: integrationID,
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		Quantity:      r.Quantity,
+	}
+	if r.FilialID != "" {
+		filialID, err := parseGuid("filial_id", r.FilialID)
+		if err != nil {
+			return nil, err
+		}
+		rest.FilialID = &filialID
+	}
+	return rest, nil
+}
+
+func parseGuid(field, s string) (guid.Guid, error) {
+	g, err := guid.ParseString(strings.TrimSpace(s))
+	if err != nil {
+		return guid.Guid{}, fmt.Errorf("%s: %w", field, err)
+	}
+	return *g, nil
+}
+
+func printDiff(w io.Writer, result *ingest.ImportResult) error {
+	var inserted, updated int
+	for _, diff := range result.Diff {
+		sign, previous := "~", "0"
+		if diff.Previous == nil {
+			sign = "+"
+			inserted++
+		} else {
+			previous = diff.Previous.String()
+			updated++
+		}
+		if _, err := fmt.Fprintf(w, "%s %s warehouse=%s product=%s %s -> %s\n",
+			sign, diff.IntegrationID, diff.WarehouseID, diff.ProductID, previous, diff.Quantity); err != nil {
+			return err
+		}
+	}
+	verb := "applied"
+	if !result.Applied {
+		verb = "dry run"
+	}
+	_, err := fmt.Fprintf(w, "%s: %d rows, %d inserted, %d updated, %d unchanged\n",
+		verb, result.Staged, inserted, updated, int(result.Staged)-inserted-updated)
+	return err
+}
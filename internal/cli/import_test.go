@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	filialID := guid.New()
+	warehouseID := guid.New()
+	productID := guid.New()
+	integrationID := guid.New()
+	input := "integration_id,product_id,warehouse_id,filial_id,quantity\n" +
+		integrationID.String() + "," + productID.String() + "," + warehouseID.String() + "," + filialID.String() + ",12.500\n" +
+		guid.New().String() + "," + productID.String() + "," + warehouseID.String() + ",,0\n"
+
+	rests, err := readAll(strings.NewReader(input), formatCSV)
+
+	require.NoError(t, err)
+	require.Len(t, rests, 2)
+	assert.Equal(t, *integrationID, rests[0].IntegrationID)
+	assert.Equal(t, filialID, rests[0].FilialID)
+	assert.Equal(t, *warehouseID, rests[0].WarehouseID)
+	assert.Equal(t, *productID, rests[0].ProductID)
+	assert.Equal(t, "12.5", rests[0].Quantity.String())
+	assert.Nil(t, rests[1].FilialID)
+}
+
+func TestReadCSVInvalid(t *testing.T) {
+	header := "filial_id,warehouse_id,product_id,quantity,integration_id\n"
+	row := func(quantity, productID string) string {
+		return guid.New().String() + "," + guid.New().String() + "," + productID + "," + quantity + "," + guid.New().String() + "\n"
+	}
+
+	_, err := readAll(strings.NewReader(header+row("1", guid.New().String())+row("abc", guid.New().String())), formatCSV)
+	assert.ErrorContains(t, err, "line 3: quantity")
+
+	_, err = readAll(strings.NewReader(header+row("1", "not-a-guid")), formatCSV)
+	assert.ErrorContains(t, err, "line 2: product_id")
+
+	_, err = readAll(strings.NewReader(header+row("-1", guid.New().String())), formatCSV)
+	assert.ErrorContains(t, err, "line 2: quantity")
+
+	_, err = readAll(strings.NewReader("filial_id,warehouse_id\n"), formatCSV)
+	assert.ErrorContains(t, err, "missing column")
+}
+
+func TestReadJSONL(t *testing.T) {
+	input := `{"warehouse_id":"` + guid.New().String() + `","product_id":"` + guid.New().String() +
+		`","quantity":"3.25","integration_id":"` + guid.New().String() + `"}` + "\n\n" +
+		`{"warehouse_id":"` + guid.New().String() + `","product_id":"` + guid.New().String() +
+		`","quantity":4,"integration_id":"` + guid.New().String() + `"}` + "\n"
+
+	rests, err := readAll(strings.NewReader(input), formatJSONL)
+
+	require.NoError(t, err)
+	require.Len(t, rests, 2)
+	assert.Equal(t, "3.25", rests[0].Quantity.String())
+	assert.Equal(t, "4", rests[1].Quantity.String())
+}
+
+func readAll(r io.Reader, format string) ([]*domain.SnapshotRest, error) {
+	next, err := readRests(r, format)
+	if err != nil {
+		return nil, err
+	}
+	rests := make([]*domain.SnapshotRest, 0)
+	for {
+		rest, err := next()
+		if err != nil || rest == nil {
+			return rests, err
+		}
+		rests = append(rests, rest)
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/DimKa163/stocks/internal/infrastructure/migration"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: migrate up | down [-steps N] | status"
+
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	migrator, err := migration.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrations(stdout, "applied", applied)
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		if err = flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			return err
+		}
+		return printMigrations(stdout, "reverted", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+			}
+			if _, err = fmt.Fprintf(stdout, "%04d_%s %s\n", status.Version, status.Name, state); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func printMigrations(w io.Writer, verb string, migrations []*migration.Migration) error {
+	if len(migrations) == 0 {
+		_, err := fmt.Fprintln(w, "nothing to do")
+		return err
+	}
+	for _, m := range migrations {
+		if _, err := fmt.Fprintf(w, "%s %04d_%s\n", verb, m.Version, m.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
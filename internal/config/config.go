@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/caarlos0/env"
+)
+
+type Config struct {
+	DatabaseURL     string        `env:"DATABASE_URL,required"`
+	HTTPAddr        string        `env:"HTTP_ADDR" envDefault:":8080"`
+	GRPCAddr        string        `env:"GRPC_ADDR" envDefault:":9090"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	ReservationTTL  time.Duration `env:"RESERVATION_TTL" envDefault:"15m"`
+	SweepInterval   time.Duration `env:"RESERVATION_SWEEP_INTERVAL" envDefault:"1m"`
+	SweepBatchSize  int           `env:"RESERVATION_SWEEP_BATCH_SIZE" envDefault:"500"`
+	InventoryLock   string        `env:"INVENTORY_REST_LOCK" envDefault:"none"`
+	RestLockNoWait  bool          `env:"REST_LOCK_NOWAIT" envDefault:"false"`
+	TxMaxAttempts   int           `env:"TX_MAX_ATTEMPTS" envDefault:"3"`
+	TxRetryDelay    time.Duration `env:"TX_RETRY_BASE_DELAY" envDefault:"10ms"`
+	TxRetryMaxDelay time.Duration `env:"TX_RETRY_MAX_DELAY" envDefault:"200ms"`
+	RestCacheSize   int           `env:"REST_CACHE_SIZE" envDefault:"100000"`
+	RestCacheTTL    time.Duration `env:"REST_CACHE_TTL" envDefault:"30s"`
+	OutboxPublisher string        `env:"OUTBOX_PUBLISHER" envDefault:"stdout"`
+	OutboxInterval  time.Duration `env:"OUTBOX_RELAY_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+}
+
+func Load() (*Config, error) {
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate rejects the settings the background workers cannot run with: a
+// ticker panics on a non-positive interval and an empty batch never drains.
+func (c *Config) validate() error {
+	switch {
+	case c.SweepInterval <= 0:
+		return errors.New("RESERVATION_SWEEP_INTERVAL must be positive")
+	case c.SweepBatchSize <= 0:
+		return errors.New("RESERVATION_SWEEP_BATCH_SIZE must be positive")
+	case c.OutboxInterval <= 0:
+		return errors.New("OUTBOX_RELAY_INTERVAL must be positive")
+	case c.OutboxBatchSize <= 0:
+		return errors.New("OUTBOX_BATCH_SIZE must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/stocks")
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 500, cfg.SweepBatchSize)
+	assert.Equal(t, 100, cfg.OutboxBatchSize)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for _, name := range []string{
+		"RESERVATION_SWEEP_INTERVAL",
+		"RESERVATION_SWEEP_BATCH_SIZE",
+		"OUTBOX_RELAY_INTERVAL",
+		"OUTBOX_BATCH_SIZE",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", "postgres://localhost/stocks")
+			t.Setenv(name, "0")
+
+			cfg, err := Load()
+
+			assert.ErrorContains(t, err, name)
+			assert.Nil(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+type RestChangeOp int
+
+const (
+	RestChangeOpINSERT RestChangeOp = iota
+	RestChangeOpUPDATE
+	RestChangeOpDELETE
+)
+
+func (op RestChangeOp) String() string {
+	return [...]string{"INSERT", "UPDATE", "DELETE"}[op]
+}
+
+// RestChange is a committed change of a rest quantity. Old is nil for an
+// inserted rest and New is nil for a deleted one. Both hold the rest key and
+// its quantity only; the rest id, the integration id and Reserved are not
+// carried.
+type RestChange struct {
+	Op  RestChangeOp
+	Old *Rest
+	New *Rest
+}
+
+// Rest returns the state after the change, or the deleted rest.
+func (rc *RestChange) Rest() *Rest {
+	if rc.New != nil {
+		return rc.New
+	}
+	return rc.Old
+}
+
+// Delta is the change of the rest quantity.
+func (rc *RestChange) Delta() decimal.Decimal {
+	delta := decimal.Zero
+	if rc.New != nil {
+		delta = delta.Add(rc.New.Quantity)
+	}
+	if rc.Old != nil {
+		delta = delta.Sub(rc.Old.Quantity)
+	}
+	return delta
+}
+
+type RestChangeHandler func(ctx context.Context, change *RestChange)
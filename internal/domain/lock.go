@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrLockNotAvailable = errors.New("row is locked by another transaction")
+
+// RowLock describes how rows read inside a transaction are locked.
+type RowLock struct {
+	Strength LockStrength
+	// NoWait fails with ErrLockNotAvailable instead of waiting for the lock.
+	NoWait bool
+}
+
+type LockStrength int
+
+const (
+	LockNone LockStrength = iota
+	LockForShare
+	LockForUpdate
+)
+
+func (ls LockStrength) String() string {
+	return [...]string{"NONE", "FOR_SHARE", "FOR_UPDATE"}[ls]
+}
+
+func ParseLockStrength(s string) (LockStrength, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return LockNone, nil
+	case "share", "for_share":
+		return LockForShare, nil
+	case "update", "for_update":
+		return LockForUpdate, nil
+	}
+	return 0, fmt.Errorf("unknown lock strength %q", s)
+}
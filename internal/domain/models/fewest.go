@@ -0,0 +1,424 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+// maxWarehouseSets bounds how many warehouse sets the FewestWarehouses search
+// evaluates. When it is exceeded the greedy plan is used instead.
+const maxWarehouseSets = 20000
+
+// jointItem is an item that can be planned together with the other items of
+// the order.
+type jointItem interface {
+	DeliveryItemer
+	priority() ChoicePriority
+	demands() []*SimpleProduct
+	// whole reports whether the demands have to come from one warehouse.
+	whole() bool
+}
+
+func (sp *SimpleProduct) priority() ChoicePriority {
+	return sp.ChoicePriority
+}
+
+func (sp *SimpleProduct) demands() []*SimpleProduct {
+	return []*SimpleProduct{sp}
+}
+
+func (sp *SimpleProduct) whole() bool {
+	return false
+}
+
+func (cp *CompositeProduct) priority() ChoicePriority {
+	return cp.ChoicePriority
+}
+
+func (cp *CompositeProduct) demands() []*SimpleProduct {
+	return cp.Products
+}
+
+func (cp *CompositeProduct) whole() bool {
+	return true
+}
+
+// FindOrder allocates every item of the order on the path. Items with the
+// FewestWarehouses priority are planned together, so the order as a whole is
+// split over as few warehouses as possible; the other items are found one by
+// one. Stock states are returned in item order and priced with the costs of
+// their path nodes.
+func FindOrder(ctx context.Context, restRepository domain.RestRepository, path *types.Path, items []DeliveryItemer) ([]*StockState, []*SkippedNode, error) {
+	if path.Len() == 0 {
+		return nil, nil, errors.New("path is empty")
+	}
+	joints := make([]jointItem, 0)
+	products := make([]*SimpleProduct, 0)
+	for _, item := range items {
+		if joint, ok := planned(item); ok {
+			joints = append(joints, joint)
+			products = append(products, joint.demands()...)
+		}
+	}
+	var (
+		jointStates  [][]*StockState
+		jointSkipped []*SkippedNode
+	)
+	if len(joints) > 0 {
+		rests, err := prefetchRests(ctx, restRepository, path, products...)
+		if err != nil {
+			return nil, nil, err
+		}
+		jointStates, jointSkipped = planFewestWarehouses(rests, path, joints)
+	}
+	stockStates := make([]*StockState, 0)
+	skipped := make([]*SkippedNode, 0)
+	next := 0
+	for _, item := range items {
+		if _, ok := planned(item); ok {
+			stockStates = append(stockStates, jointStates[next]...)
+			next++
+			continue
+		}
+		states, itemSkipped, err := item.Find(ctx, restRepository, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		stockStates = append(stockStates, states...)
+		skipped = append(skipped, itemSkipped...)
+	}
+	price(stockStates, path)
+	return stockStates, append(skipped, jointSkipped...), nil
+}
+
+// price sets the cost and lead time of the stock states taken from the path.
+func price(stockStates []*StockState, path *types.Path) {
+	for _, stockState := range stockStates {
+		if stockState.Produce {
+			continue
+		}
+		cost := path.Cost(*stockState.WarehouseID)
+		stockState.Cost = cost.Cost.Mul(stockState.Quantity)
+		stockState.LeadTime = cost.LeadTime
+	}
+}
+
+func planned(item DeliveryItemer) (jointItem, bool) {
+	joint, ok := item.(jointItem)
+	if !ok || joint.priority() != FewestWarehouses {
+		return nil, false
+	}
+	return joint, true
+}
+
+// unit is a group of demands the planner covers together. A whole unit is a
+// composite product gathered at one warehouse, it is covered there completely
+// or not at all.
+type unit struct {
+	demands []int
+	whole   bool
+}
+
+// planFewestWarehouses picks the smallest set of warehouses that covers as
+// much of the items as the whole path does. Among sets of the same size the
+// one coming first in path order wins. A composite product is gathered at one
+// warehouse like with the other priorities; only when no warehouse holds all
+// of it are its products planned one by one. Every item gets its stock
+// states, quantities no warehouse can cover are produced.
+func planFewestWarehouses(rests rests, path *types.Path, items []jointItem) ([][]*StockState, []*SkippedNode) {
+	planner := &warehousePlanner{rests: rests}
+	skipped := make([]*SkippedNode, 0)
+	for _, item := range items {
+		indexes := make([]int, 0, len(item.demands()))
+		for _, demand := range item.demands() {
+			indexes = append(indexes, len(planner.demands))
+			planner.demands = append(planner.demands, demand)
+			for _, node := range path.Nodes() {
+				if demand.ignores(node) {
+					skipped = append(skipped, newSkippedNode(node, demand.ProductID, SkipReasonIgnored))
+				}
+			}
+		}
+		if item.whole() && gatherable(rests, path, item.demands()) {
+			planner.units = append(planner.units, unit{demands: indexes, whole: true})
+			continue
+		}
+		for _, i := range indexes {
+			planner.units = append(planner.units, unit{demands: []int{i}})
+		}
+	}
+	for _, node := range path.Nodes() {
+		if planner.stocks(node) {
+			planner.nodes = append(planner.nodes, node)
+		}
+	}
+	taken := planner.allocate(planner.best())
+	states := make([][]*StockState, 0, len(items))
+	i := 0
+	for _, item := range items {
+		itemStates := make([]*StockState, 0, len(item.demands()))
+		for range item.demands() {
+			itemStates = append(itemStates, taken[i]...)
+			i++
+		}
+		states = append(states, itemStates)
+	}
+	return states, skipped
+}
+
+// gatherable reports whether a warehouse of the path holds all of the demands.
+func gatherable(available rests, path *types.Path, demands []*SimpleProduct) bool {
+	for _, node := range path.Nodes() {
+		needed := make(rests)
+		all := true
+		for _, demand := range demands {
+			key := restKey{filialID: demand.FilialID, warehouseID: node, productID: demand.ProductID}
+			needed[key] = needed[key].Add(demand.Quantity)
+			if demand.ignores(node) || available[key].LessThan(needed[key]) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+type warehousePlanner struct {
+	rests   rests
+	demands []*SimpleProduct
+	// units cover the demands, whole units come first at every node.
+	units []unit
+	// nodes are the path nodes with stock for at least one demand, in path order.
+	nodes []guid.Guid
+}
+
+func (p *warehousePlanner) stocks(node guid.Guid) bool {
+	for _, demand := range p.demands {
+		if !demand.ignores(node) && p.rests.quantity(demand.FilialID, node, demand.ProductID).IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// best returns the indexes of the chosen nodes in path order.
+func (p *warehousePlanner) best() []int {
+	all := make([]int, len(p.nodes))
+	for i := range all {
+		all[i] = i
+	}
+	target := p.covered(all)
+	if target.IsZero() {
+		return nil
+	}
+	greedy := p.greedy()
+	budget := maxWarehouseSets
+	for size := 1; size <= len(greedy); size++ {
+		set, found := p.search(size, target, &budget)
+		if found {
+			return set
+		}
+		if budget <= 0 {
+			break
+		}
+	}
+	return greedy
+}
+
+// search walks the sets of the size in lexicographic path order and returns
+// the first one covering the target.
+func (p *warehousePlanner) search(size int, target decimal.Decimal, budget *int) ([]int, bool) {
+	if size > len(p.nodes) {
+		return nil, false
+	}
+	set := make([]int, size)
+	for i := range set {
+		set[i] = i
+	}
+	for {
+		if *budget <= 0 {
+			return nil, false
+		}
+		*budget--
+		if p.covered(set).GreaterThanOrEqual(target) {
+			return set, true
+		}
+		i := size - 1
+		for i >= 0 && set[i] == len(p.nodes)-size+i {
+			i--
+		}
+		if i < 0 {
+			return nil, false
+		}
+		set[i]++
+		for j := i + 1; j < size; j++ {
+			set[j] = set[j-1] + 1
+		}
+	}
+}
+
+// greedy repeatedly takes the node covering the most of what is still
+// missing, the nearest one on ties.
+func (p *warehousePlanner) greedy() []int {
+	remaining := make([]decimal.Decimal, len(p.demands))
+	for i, demand := range p.demands {
+		remaining[i] = demand.Quantity
+	}
+	capacity := make(rests)
+	chosen := make(map[int]bool)
+	for {
+		best, bestGain := -1, decimal.Zero
+		for i, node := range p.nodes {
+			if chosen[i] {
+				continue
+			}
+			gain := p.take(node, remaining, capacity, false, false)
+			if gain.GreaterThan(bestGain) {
+				best, bestGain = i, gain
+			}
+		}
+		if best < 0 {
+			break
+		}
+		chosen[best] = true
+		p.take(p.nodes[best], remaining, capacity, true, false)
+	}
+	set := make([]int, 0, len(chosen))
+	for i := range p.nodes {
+		if chosen[i] {
+			set = append(set, i)
+		}
+	}
+	return set
+}
+
+// take covers the remaining demands from the node and returns the covered
+// quantity. Without apply remaining and capacity are left untouched. With
+// split whole units are covered like the others.
+func (p *warehousePlanner) take(node guid.Guid, remaining []decimal.Decimal, capacity rests, apply, split bool) decimal.Decimal {
+	used := make(rests)
+	total := decimal.Zero
+	for _, whole := range []bool{true, false} {
+		for _, u := range p.units {
+			if u.whole != whole {
+				continue
+			}
+			if u.whole && !split {
+				total = total.Add(p.takeWhole(node, u, remaining, capacity, used, apply))
+				continue
+			}
+			for _, i := range u.demands {
+				total = total.Add(p.takeDemand(node, i, remaining, capacity, used, apply))
+			}
+		}
+	}
+	if apply {
+		for key, quantity := range used {
+			capacity[key] = capacity[key].Add(quantity)
+		}
+	}
+	return total
+}
+
+func (p *warehousePlanner) takeDemand(node guid.Guid, i int, remaining []decimal.Decimal, capacity, used rests, apply bool) decimal.Decimal {
+	demand := p.demands[i]
+	if demand.ignores(node) || !remaining[i].IsPositive() {
+		return decimal.Zero
+	}
+	key := restKey{filialID: demand.FilialID, warehouseID: node, productID: demand.ProductID}
+	covered := decimal.Min(remaining[i], p.rests[key].Sub(capacity[key]).Sub(used[key]))
+	if !covered.IsPositive() {
+		return decimal.Zero
+	}
+	used[key] = used[key].Add(covered)
+	if apply {
+		remaining[i] = remaining[i].Sub(covered)
+	}
+	return covered
+}
+
+// takeWhole covers all demands of the unit from the node or none of them.
+func (p *warehousePlanner) takeWhole(node guid.Guid, u unit, remaining []decimal.Decimal, capacity, used rests, apply bool) decimal.Decimal {
+	needed := make(rests)
+	total := decimal.Zero
+	for _, i := range u.demands {
+		demand := p.demands[i]
+		if demand.ignores(node) || !remaining[i].Equal(demand.Quantity) {
+			return decimal.Zero
+		}
+		key := restKey{filialID: demand.FilialID, warehouseID: node, productID: demand.ProductID}
+		needed[key] = needed[key].Add(demand.Quantity)
+		if p.rests[key].Sub(capacity[key]).Sub(used[key]).LessThan(needed[key]) {
+			return decimal.Zero
+		}
+		total = total.Add(demand.Quantity)
+	}
+	for key, quantity := range needed {
+		used[key] = used[key].Add(quantity)
+	}
+	if apply {
+		for _, i := range u.demands {
+			remaining[i] = decimal.Zero
+		}
+	}
+	return total
+}
+
+func (p *warehousePlanner) covered(set []int) decimal.Decimal {
+	remaining := make([]decimal.Decimal, len(p.demands))
+	for i, demand := range p.demands {
+		remaining[i] = demand.Quantity
+	}
+	capacity := make(rests)
+	total := decimal.Zero
+	for _, i := range set {
+		total = total.Add(p.take(p.nodes[i], remaining, capacity, true, false))
+	}
+	return total
+}
+
+// allocate turns the chosen nodes into stock states per demand. Whole units
+// none of the chosen nodes could gather take what is left there one by one.
+func (p *warehousePlanner) allocate(set []int) [][]*StockState {
+	taken := make([][]*StockState, len(p.demands))
+	remaining := make([]decimal.Decimal, len(p.demands))
+	for i, demand := range p.demands {
+		remaining[i] = demand.Quantity
+	}
+	capacity := make(rests)
+	for _, split := range []bool{false, true} {
+		for _, n := range set {
+			node := p.nodes[n]
+			before := make([]decimal.Decimal, len(remaining))
+			copy(before, remaining)
+			p.take(node, remaining, capacity, true, split)
+			for i, demand := range p.demands {
+				if covered := before[i].Sub(remaining[i]); covered.IsPositive() {
+					taken[i] = append(taken[i], &StockState{
+						ProductID:   demand.ProductID,
+						Quantity:    covered,
+						WarehouseID: &node,
+					})
+				}
+			}
+		}
+	}
+	for i, demand := range p.demands {
+		if remaining[i].IsPositive() {
+			taken[i] = append(taken[i], &StockState{
+				ProductID: demand.ProductID,
+				Quantity:  remaining[i],
+				Produce:   true,
+			})
+		}
+	}
+	return taken
+}
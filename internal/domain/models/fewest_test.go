@@ -0,0 +1,309 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRest(filialID, warehouseID, productID guid.Guid, quantity int64) *domain.Rest {
+	return &domain.Rest{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(quantity), ProductID: productID, WarehouseID: warehouseID}
+}
+
+func newWarehouses(n int) ([]guid.Guid, *types.Path) {
+	nodes := make([]guid.Guid, n)
+	for i := range nodes {
+		nodes[i] = *guid.New()
+	}
+	return nodes, types.NewPathFromSlice(nodes)
+}
+
+// assertStockStates compares costs by value, an unpriced state may carry
+// either representation of zero.
+func assertStockStates(t *testing.T, expected, actual []*StockState) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		exp, act := *expected[i], *actual[i]
+		assert.True(t, exp.Cost.Equal(act.Cost), "cost of state %d: expected %s, actual %s", i, exp.Cost, act.Cost)
+		exp.Cost, act.Cost = decimal.Zero, decimal.Zero
+		assert.Equal(t, exp, act)
+	}
+}
+
+func TestFewestWarehousesSimpleProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	w, path := newWarehouses(3)
+	rep := mocks.NewMockRestRepository(ctrl)
+	rep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{productID}).Return([]*domain.Rest{
+		newRest(filialID, w[0], productID, 2),
+		newRest(filialID, w[1], productID, 1),
+		newRest(filialID, w[2], productID, 5),
+	}, nil)
+
+	states, skipped, err := NewSimpleProduct(productID, decimal.NewFromInt(5), false, FewestWarehouses, filialID).Find(ctx, rep, path)
+
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, []*StockState{{ProductID: productID, Quantity: decimal.NewFromInt(5), WarehouseID: &w[2]}}, states)
+}
+
+func TestFindOrder(t *testing.T) {
+	filialID := *guid.New()
+	productA := *guid.New()
+	productB := *guid.New()
+	productC := *guid.New()
+	cases := []struct {
+		Name  string
+		Rests func(w []guid.Guid) []*domain.Rest
+		Items func() []DeliveryItemer
+		Exp   func(w []guid.Guid) []*StockState
+	}{
+		{
+			Name: "Order should be gathered at the warehouse holding all of it",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productA, 5),
+					newRest(filialID, w[1], productB, 5),
+					newRest(filialID, w[2], productA, 1),
+					newRest(filialID, w[2], productB, 2),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewSimpleProduct(productA, decimal.NewFromInt(1), false, FewestWarehouses, filialID),
+					NewSimpleProduct(productB, decimal.NewFromInt(2), false, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productA, Quantity: decimal.NewFromInt(1), WarehouseID: &w[2]},
+					{ProductID: productB, Quantity: decimal.NewFromInt(2), WarehouseID: &w[2]},
+				}
+			},
+		},
+		{
+			Name: "Nearest set should win among sets of the same size",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productA, 1),
+					newRest(filialID, w[1], productA, 3),
+					newRest(filialID, w[1], productB, 3),
+					newRest(filialID, w[2], productA, 3),
+					newRest(filialID, w[2], productB, 3),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewSimpleProduct(productA, decimal.NewFromInt(2), false, FewestWarehouses, filialID),
+					NewSimpleProduct(productB, decimal.NewFromInt(2), false, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productA, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+					{ProductID: productB, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+				}
+			},
+		},
+		{
+			Name: "Shortage should be produced after the fewest warehouses are used up",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productA, 3),
+					newRest(filialID, w[2], productA, 4),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewSimpleProduct(productA, decimal.NewFromInt(10), false, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productA, Quantity: decimal.NewFromInt(3), WarehouseID: &w[0]},
+					{ProductID: productA, Quantity: decimal.NewFromInt(4), WarehouseID: &w[2]},
+					{ProductID: productA, Quantity: decimal.NewFromInt(3), Produce: true},
+				}
+			},
+		},
+		{
+			Name: "Composite product should be gathered at one warehouse",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productC, 1),
+					newRest(filialID, w[0], productA, 1),
+					newRest(filialID, w[1], productB, 1),
+					newRest(filialID, w[2], productA, 1),
+					newRest(filialID, w[2], productB, 1),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewSimpleProduct(productC, decimal.NewFromInt(1), false, FewestWarehouses, filialID),
+					NewCompositeProduct([]*SimpleProduct{
+						NewSimpleProduct(productA, decimal.NewFromInt(1), false, Nearest, filialID),
+						NewSimpleProduct(productB, decimal.NewFromInt(1), false, Nearest, filialID),
+					}, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productC, Quantity: decimal.NewFromInt(1), WarehouseID: &w[0]},
+					{ProductID: productA, Quantity: decimal.NewFromInt(1), WarehouseID: &w[2]},
+					{ProductID: productB, Quantity: decimal.NewFromInt(1), WarehouseID: &w[2]},
+				}
+			},
+		},
+		{
+			Name: "Composite product should be split when no warehouse holds all of it",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productA, 1),
+					newRest(filialID, w[1], productB, 1),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewCompositeProduct([]*SimpleProduct{
+						NewSimpleProduct(productA, decimal.NewFromInt(1), false, Nearest, filialID),
+						NewSimpleProduct(productB, decimal.NewFromInt(1), false, Nearest, filialID),
+					}, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productA, Quantity: decimal.NewFromInt(1), WarehouseID: &w[0]},
+					{ProductID: productB, Quantity: decimal.NewFromInt(1), WarehouseID: &w[1]},
+				}
+			},
+		},
+		{
+			Name: "Composite product should be planned with the rest of the order",
+			Rests: func(w []guid.Guid) []*domain.Rest {
+				return []*domain.Rest{
+					newRest(filialID, w[0], productA, 1),
+					newRest(filialID, w[1], productA, 1),
+					newRest(filialID, w[1], productB, 2),
+				}
+			},
+			Items: func() []DeliveryItemer {
+				return []DeliveryItemer{
+					NewSimpleProduct(productA, decimal.NewFromInt(1), false, FewestWarehouses, filialID),
+					NewCompositeProduct([]*SimpleProduct{
+						NewSimpleProduct(productB, decimal.NewFromInt(2), false, Nearest, filialID),
+					}, FewestWarehouses, filialID),
+				}
+			},
+			Exp: func(w []guid.Guid) []*StockState {
+				return []*StockState{
+					{ProductID: productA, Quantity: decimal.NewFromInt(1), WarehouseID: &w[1]},
+					{ProductID: productB, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+				}
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			w, path := newWarehouses(3)
+			rep := mocks.NewMockRestRepository(ctrl)
+			rep.EXPECT().GetMany(ctx, filialID, path.Nodes(), gomock.Any()).Return(tt.Rests(w), nil)
+
+			states, skipped, err := FindOrder(ctx, rep, path, tt.Items())
+
+			require.NoError(t, err)
+			assert.Empty(t, skipped)
+			assertStockStates(t, tt.Exp(w), states)
+		})
+	}
+}
+
+func TestFindOrderKeepsOtherPriorities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productA := *guid.New()
+	productB := *guid.New()
+	w, path := newWarehouses(2)
+	rep := mocks.NewMockRestRepository(ctrl)
+	rests := []*domain.Rest{
+		newRest(filialID, w[0], productA, 1),
+		newRest(filialID, w[1], productA, 2),
+		newRest(filialID, w[0], productB, 2),
+	}
+	rep.EXPECT().GetMany(ctx, filialID, path.Nodes(), gomock.Any()).Return(rests, nil).Times(2)
+
+	states, _, err := FindOrder(ctx, rep, path, []DeliveryItemer{
+		NewSimpleProduct(productB, decimal.NewFromInt(2), false, Nearest, filialID),
+		NewSimpleProduct(productA, decimal.NewFromInt(2), false, FewestWarehouses, filialID),
+	})
+
+	require.NoError(t, err)
+	assertStockStates(t, []*StockState{
+		{ProductID: productB, Quantity: decimal.NewFromInt(2), WarehouseID: &w[0]},
+		{ProductID: productA, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+	}, states)
+}
+
+func TestFewestWarehousesBoundedSearch(t *testing.T) {
+	filialID := *guid.New()
+	productID := *guid.New()
+	w, path := newWarehouses(40)
+	r := make(rests)
+	for _, node := range w {
+		r[restKey{filialID: filialID, warehouseID: node, productID: productID}] = decimal.NewFromInt(1)
+	}
+	product := NewSimpleProduct(productID, decimal.NewFromInt(20), false, FewestWarehouses, filialID)
+
+	states, _ := planFewestWarehouses(r, path, []jointItem{product})
+
+	require.Len(t, states[0], 20)
+	for i, state := range states[0] {
+		assert.Equal(t, &w[i], state.WarehouseID)
+	}
+}
+
+func TestFindOrderPricesStockStates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	near := *guid.New()
+	far := *guid.New()
+	path := types.NewPath(2)
+	path.AddNodeWithCost(near, types.NodeCost{Cost: decimal.RequireFromString("1.5"), LeadTime: 24 * time.Hour})
+	path.AddNodeWithCost(far, types.NodeCost{Cost: decimal.NewFromInt(1), LeadTime: 72 * time.Hour})
+	rep := mocks.NewMockRestRepository(ctrl)
+	rep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{productID}).Return([]*domain.Rest{
+		newRest(filialID, near, productID, 2),
+		newRest(filialID, far, productID, 1),
+	}, nil)
+
+	states, _, err := FindOrder(ctx, rep, path, []DeliveryItemer{
+		NewSimpleProduct(productID, decimal.NewFromInt(4), false, Nearest, filialID),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+	assert.True(t, decimal.RequireFromString("3").Equal(states[0].Cost))
+	assert.Equal(t, 24*time.Hour, states[0].LeadTime)
+	assert.True(t, decimal.RequireFromString("1").Equal(states[1].Cost))
+	assert.Equal(t, 72*time.Hour, states[1].LeadTime)
+	assert.True(t, states[2].Produce)
+	assert.True(t, states[2].Cost.IsZero())
+	assert.Zero(t, states[2].LeadTime)
+}
@@ -3,6 +3,9 @@ package models
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/DimKa163/stocks/internal/shared/collection"
@@ -16,10 +19,35 @@ type ChoicePriority int
 const (
 	Nearest ChoicePriority = iota
 	Farthest
+	// FewestWarehouses splits the order over as few warehouses as possible.
+	// A composite product is still gathered at one warehouse when any holds
+	// all of it.
+	FewestWarehouses
+	// Cheapest takes stock from the nodes with the lowest unit cost that meet
+	// the product's deadline.
+	Cheapest
 )
 
+func (cp ChoicePriority) String() string {
+	return [...]string{"Nearest", "Farthest", "FewestWarehouses", "Cheapest"}[cp]
+}
+
+func ParseChoicePriority(s string) (ChoicePriority, error) {
+	switch strings.ToLower(s) {
+	case "", "nearest":
+		return Nearest, nil
+	case "farthest":
+		return Farthest, nil
+	case "fewest_warehouses", "fewestwarehouses":
+		return FewestWarehouses, nil
+	case "cheapest":
+		return Cheapest, nil
+	}
+	return 0, fmt.Errorf("unknown choice priority %q", s)
+}
+
 type DeliveryItemer interface {
-	Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, error)
+	Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, []*SkippedNode, error)
 }
 
 type InventoryProduct struct {
@@ -27,6 +55,22 @@ type InventoryProduct struct {
 	Quantity     decimal.Decimal
 	IsLocal      bool
 	IgnoredNodes []guid.Guid
+	// Deadline is the longest acceptable lead time, zero for none. Only the
+	// Cheapest priority honours it.
+	Deadline time.Duration
+}
+
+func (ip *InventoryProduct) ignores(node guid.Guid) bool {
+	for _, ignored := range ip.IgnoredNodes {
+		if ignored == node {
+			return true
+		}
+	}
+	return false
+}
+
+func (ip *InventoryProduct) tooSlow(path *types.Path, node guid.Guid) bool {
+	return ip.Deadline > 0 && path.Cost(node).LeadTime > ip.Deadline
 }
 
 type SimpleProduct struct {
@@ -47,9 +91,21 @@ func NewSimpleProduct(productID guid.Guid, quantity decimal.Decimal, isLocal boo
 	}
 }
 
-func (sp *SimpleProduct) Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, error) {
+func (sp *SimpleProduct) Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, []*SkippedNode, error) {
 	if path.Len() == 0 {
-		return nil, errors.New("path is empty")
+		return nil, nil, errors.New("path is empty")
+	}
+	rests, err := prefetchRests(ctx, restRepository, path, sp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sp.find(rests, path)
+}
+
+func (sp *SimpleProduct) find(rests rests, path *types.Path) ([]*StockState, []*SkippedNode, error) {
+	if sp.ChoicePriority == FewestWarehouses {
+		states, skipped := planFewestWarehouses(rests, path, []jointItem{sp})
+		return states[0], skipped, nil
 	}
 	var strategy func(fn func(node guid.Guid) (bool, error)) error
 	switch sp.ChoicePriority {
@@ -57,23 +113,31 @@ func (sp *SimpleProduct) Find(ctx context.Context, restRepository domain.RestRep
 		strategy = path.Foreach
 	case Farthest:
 		strategy = path.ForeachReverse
+	case Cheapest:
+		strategy = path.ForeachCheapest
 	}
 	remainingQuantity := sp.Quantity
 	stockStates := make([]*StockState, 0)
+	skipped := make([]*SkippedNode, 0)
 	err := strategy(func(node guid.Guid) (bool, error) {
-		rest, err := restRepository.Get(ctx, sp.FilialID, node, sp.ProductID)
-		if err != nil {
-			return false, err
+		if sp.ignores(node) {
+			skipped = append(skipped, newSkippedNode(node, sp.ProductID, SkipReasonIgnored))
+			return true, nil
 		}
-		if rest.Quantity.IsZero() {
+		if sp.ChoicePriority == Cheapest && sp.tooSlow(path, node) {
+			skipped = append(skipped, newSkippedNode(node, sp.ProductID, SkipReasonLeadTime))
+			return true, nil
+		}
+		quantity := rests.quantity(sp.FilialID, node, sp.ProductID)
+		if !quantity.IsPositive() {
 			return true, nil
 		}
 		var covered decimal.Decimal
-		if remainingQuantity.LessThanOrEqual(rest.Quantity) {
+		if remainingQuantity.LessThanOrEqual(quantity) {
 			covered = remainingQuantity
 			remainingQuantity = remainingQuantity.Sub(covered)
 		} else {
-			covered = rest.Quantity
+			covered = quantity
 			remainingQuantity = remainingQuantity.Sub(covered)
 		}
 		stockStates = append(stockStates, &StockState{
@@ -86,7 +150,7 @@ func (sp *SimpleProduct) Find(ctx context.Context, restRepository domain.RestRep
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if remainingQuantity.GreaterThan(decimal.Zero) {
@@ -96,7 +160,7 @@ func (sp *SimpleProduct) Find(ctx context.Context, restRepository domain.RestRep
 			Produce:   true,
 		})
 	}
-	return stockStates, nil
+	return stockStates, skipped, nil
 }
 
 type CompositeProduct struct {
@@ -113,9 +177,17 @@ func NewCompositeProduct(products []*SimpleProduct, choice ChoicePriority, filia
 	}
 }
 
-func (cp *CompositeProduct) Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, error) {
+func (cp *CompositeProduct) Find(ctx context.Context, restRepository domain.RestRepository, path *types.Path) ([]*StockState, []*SkippedNode, error) {
 	if path.Len() == 0 {
-		return nil, errors.New("path is empty")
+		return nil, nil, errors.New("path is empty")
+	}
+	rests, err := prefetchRests(ctx, restRepository, path, cp.Products...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cp.ChoicePriority == FewestWarehouses {
+		states, skipped := planFewestWarehouses(rests, path, []jointItem{cp})
+		return states[0], skipped, nil
 	}
 	var strategy func(fn func(node guid.Guid) (bool, error)) error
 	switch cp.ChoicePriority {
@@ -123,30 +195,43 @@ func (cp *CompositeProduct) Find(ctx context.Context, restRepository domain.Rest
 		strategy = path.Foreach
 	case Farthest:
 		strategy = path.ForeachReverse
+	case Cheapest:
+		strategy = path.ForeachCheapest
 	}
 	stockStates := make([]*StockState, 0)
+	skipped := make([]*SkippedNode, 0)
 	allAtOne := false
-	err := strategy(func(node guid.Guid) (bool, error) {
+	err = strategy(func(node guid.Guid) (bool, error) {
+		nodeSkipped := false
+		for _, product := range cp.Products {
+			if product.ignores(node) {
+				skipped = append(skipped, newSkippedNode(node, product.ProductID, SkipReasonIgnored))
+				nodeSkipped = true
+			} else if cp.ChoicePriority == Cheapest && product.tooSlow(path, node) {
+				skipped = append(skipped, newSkippedNode(node, product.ProductID, SkipReasonLeadTime))
+				nodeSkipped = true
+			}
+		}
+		if nodeSkipped {
+			return true, nil
+		}
 		remainingMap := make(map[guid.Guid]decimal.Decimal, len(cp.Products))
 		for _, product := range cp.Products {
 			remainingMap[product.ProductID] = product.Quantity
 		}
 		for _, product := range cp.Products {
-			rest, err := restRepository.Get(ctx, cp.FilialID, node, product.ProductID)
-			if err != nil {
-				return false, err
-			}
-			if rest.Quantity.IsZero() {
+			quantity := rests.quantity(product.FilialID, node, product.ProductID)
+			if !quantity.IsPositive() {
 				return true, nil
 			}
-			if remainingMap[product.ProductID].GreaterThan(rest.Quantity) {
+			if remainingMap[product.ProductID].GreaterThan(quantity) {
 				return true, nil
 			}
 			var covered decimal.Decimal
-			if remainingMap[product.ProductID].LessThanOrEqual(rest.Quantity) {
+			if remainingMap[product.ProductID].LessThanOrEqual(quantity) {
 				covered = remainingMap[product.ProductID]
 			} else {
-				covered = rest.Quantity
+				covered = quantity
 			}
 			remainingMap[product.ProductID] = remainingMap[product.ProductID].Sub(covered)
 		}
@@ -169,24 +254,31 @@ func (cp *CompositeProduct) Find(ctx context.Context, restRepository domain.Rest
 		return !covered, nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if !allAtOne {
+		// Products are allocated one by one and report their own skips.
+		skipped = skipped[:0]
 		for _, product := range cp.Products {
-			product.ChoicePriority = Nearest
-			if product.IsLocal {
+			switch {
+			case cp.ChoicePriority == Cheapest:
+				product.ChoicePriority = Cheapest
+			case product.IsLocal:
 				product.ChoicePriority = Farthest
+			default:
+				product.ChoicePriority = Nearest
 			}
-			stocks, err := product.Find(ctx, restRepository, path)
+			stocks, productSkipped, err := product.find(rests, path)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			for _, stock := range stocks {
 				stockStates = append(stockStates, stock)
 			}
+			skipped = append(skipped, productSkipped...)
 		}
 	}
-	return stockStates, nil
+	return stockStates, skipped, nil
 }
 
 type InventoryResult int
@@ -202,14 +294,48 @@ func (ir InventoryResult) String() string {
 	return [...]string{"AllInStockAtOne", "AllInStockAtSeveral", "PartiallyInStock", "AllToProduce"}[ir]
 }
 
+// InventoryState is the allocation of an order. Cost is the total shipping
+// cost and LeadTime the longest lead time of the stock states.
 type InventoryState struct {
-	Result      InventoryResult
-	StockStates []*StockState
+	Result       InventoryResult
+	StockStates  []*StockState
+	SkippedNodes []*SkippedNode
+	Cost         decimal.Decimal
+	LeadTime     time.Duration
 }
 
+// StockState is a quantity taken from a warehouse or to be produced. Cost and
+// LeadTime come from the warehouse's path node and are zero when produced.
 type StockState struct {
 	ProductID   guid.Guid
 	Quantity    decimal.Decimal
 	WarehouseID *guid.Guid
 	Produce     bool
+	Cost        decimal.Decimal
+	LeadTime    time.Duration
+}
+
+type SkipReason int
+
+const (
+	SkipReasonIgnored SkipReason = iota
+	SkipReasonRestUnavailable
+	SkipReasonPickupOnly
+	SkipReasonLeadTime
+)
+
+func (sr SkipReason) String() string {
+	return [...]string{"Ignored", "RestUnavailable", "PickupOnly", "LeadTime"}[sr]
+}
+
+// SkippedNode is a path node the allocation did not take stock from although
+// it was walked. ProductID is nil when the node was skipped for every product.
+type SkippedNode struct {
+	WarehouseID guid.Guid
+	ProductID   *guid.Guid
+	Reason      SkipReason
+}
+
+func newSkippedNode(warehouseID, productID guid.Guid, reason SkipReason) *SkippedNode {
+	return &SkippedNode{WarehouseID: warehouseID, ProductID: &productID, Reason: reason}
 }
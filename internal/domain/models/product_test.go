@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/DimKa163/stocks/internal/shared/types"
@@ -30,17 +31,19 @@ func TestSimpleProduct(t *testing.T) {
 				filialID := guid.New()
 				warehouseID := guid.New()
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(100),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID,
-				}, nil)
 				rep = mockRep
 				path := types.NewPath(1)
 				path.AddNode(*warehouseID)
+				mockRep.EXPECT().GetMany(ctx, *filialID, path.Nodes(), []guid.Guid{*prdID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(100),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID,
+					},
+				}, nil)
 				p = path
 				prd = NewSimpleProduct(*prdID, decimal.NewFromInt(3), true, Nearest, *filialID)
 				exp = []*StockState{
@@ -65,26 +68,28 @@ func TestSimpleProduct(t *testing.T) {
 				warehouseID1 := guid.New()
 				warehouseID2 := guid.New()
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID1, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(1),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID1,
-				}, nil)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID2, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(2),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID2,
-				}, nil)
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(*warehouseID1)
 				path.AddNode(*warehouseID2)
+				mockRep.EXPECT().GetMany(ctx, *filialID, path.Nodes(), []guid.Guid{*prdID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(1),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(2),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID2,
+					},
+				}, nil)
 				p = path
 				prd = NewSimpleProduct(*prdID, decimal.NewFromInt(3), false, Nearest, *filialID)
 				exp = []*StockState{
@@ -114,26 +119,28 @@ func TestSimpleProduct(t *testing.T) {
 				warehouseID1 := guid.New()
 				warehouseID2 := guid.New()
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID1, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(1),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID1,
-				}, nil)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID2, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(2),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID2,
-				}, nil)
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(*warehouseID1)
 				path.AddNode(*warehouseID2)
+				mockRep.EXPECT().GetMany(ctx, *filialID, path.Nodes(), []guid.Guid{*prdID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(1),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(2),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID2,
+					},
+				}, nil)
 				p = path
 				prd = NewSimpleProduct(*prdID, decimal.NewFromInt(5), false, Nearest, *filialID)
 				exp = []*StockState{
@@ -167,18 +174,20 @@ func TestSimpleProduct(t *testing.T) {
 				filialID := guid.New()
 				warehouseID := guid.New()
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, *filialID, *warehouseID, *prdID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(100),
-					ProductID:     *prdID,
-					WarehouseID:   *warehouseID,
-				}, nil)
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(*guid.New())
 				path.AddNode(*warehouseID)
+				mockRep.EXPECT().GetMany(ctx, *filialID, path.Nodes(), []guid.Guid{*prdID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(100),
+						ProductID:     *prdID,
+						WarehouseID:   *warehouseID,
+					},
+				}, nil)
 				p = path
 				prd = NewSimpleProduct(*prdID, decimal.NewFromInt(3), false, Farthest, *filialID)
 				exp = []*StockState{
@@ -195,7 +204,7 @@ func TestSimpleProduct(t *testing.T) {
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
 			ctx, prd, rep, p, exp := tt.Arrange()
-			sut, err := prd.Find(ctx, rep, p)
+			sut, _, err := prd.Find(ctx, rep, p)
 			assert.NoError(t, err)
 			assert.Equal(t, exp, sut)
 		})
@@ -232,26 +241,28 @@ func TestCompositeProduct(t *testing.T) {
 				}
 				prd = NewCompositeProduct([]*SimpleProduct{prod1, prod2}, Nearest, filialID)
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(100),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil)
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(50),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil)
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(warehouseID1)
 				path.AddNode(warehouseID2)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prod1.ProductID, prod2.ProductID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(100),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(50),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+				}, nil)
 				p = path
 				exp = []*StockState{
 					{
@@ -293,42 +304,44 @@ func TestCompositeProduct(t *testing.T) {
 				}
 				prd = NewCompositeProduct([]*SimpleProduct{prod1, prod2}, Nearest, filialID)
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(50),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(50),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(warehouseID1)
 				path.AddNode(warehouseID2)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prod1.ProductID, prod2.ProductID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(50),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(50),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+				}, nil)
 				p = path
 				exp = []*StockState{
 					{
@@ -369,42 +382,44 @@ func TestCompositeProduct(t *testing.T) {
 				}
 				prd = NewCompositeProduct([]*SimpleProduct{prod1, prod2}, Nearest, filialID)
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(100),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(20),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(warehouseID1)
 				path.AddNode(warehouseID2)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prod1.ProductID, prod2.ProductID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(100),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(20),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+				}, nil)
 				p = path
 				exp = []*StockState{
 					{
@@ -448,80 +463,78 @@ func TestCompositeProduct(t *testing.T) {
 				}
 				prd = NewCompositeProduct([]*SimpleProduct{prod1, prod2}, Nearest, filialID)
 				mockRep := mocks.NewMockRestRepository(ctrl)
-				// 1-st wh
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(100),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID1, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID1,
-				}, nil).AnyTimes()
-				// 2-nd wh
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID2, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(20),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				// 3-rd wh
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID3, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID3, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				// 4-rd wh
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID4, prod1.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(0),
-					ProductID:     prod1.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
-				mockRep.EXPECT().Get(ctx, filialID, warehouseID4, prod2.ProductID).Return(&domain.Rest{
-					RestID:        *guid.New(),
-					FilialID:      &filialID,
-					IntegrationID: guid.New(),
-					Quantity:      decimal.NewFromInt(20),
-					ProductID:     prod2.ProductID,
-					WarehouseID:   warehouseID2,
-				}, nil).AnyTimes()
 				rep = mockRep
 				path := types.NewPath(3)
 				path.AddNode(warehouseID1)
 				path.AddNode(warehouseID2)
 				path.AddNode(warehouseID3)
 				path.AddNode(warehouseID4)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prod1.ProductID, prod2.ProductID}).Return([]*domain.Rest{
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(100),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID1,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(20),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID2,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID3,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID3,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(0),
+						ProductID:     prod1.ProductID,
+						WarehouseID:   warehouseID4,
+					},
+					{
+						RestID:        *guid.New(),
+						FilialID:      &filialID,
+						IntegrationID: guid.New(),
+						Quantity:      decimal.NewFromInt(20),
+						ProductID:     prod2.ProductID,
+						WarehouseID:   warehouseID4,
+					},
+				}, nil)
 				p = path
 				exp = []*StockState{
 					{
@@ -542,9 +555,270 @@ func TestCompositeProduct(t *testing.T) {
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
 			ctx, prd, rep, p, exp := tt.Arrange()
-			sut, err := prd.Find(ctx, rep, p)
+			sut, _, err := prd.Find(ctx, rep, p)
 			assert.NoError(t, err)
 			assert.Equal(t, exp, sut)
 		})
 	}
 }
+
+func TestIgnoredNodes(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Arrange func() (ctx context.Context, prd DeliveryItemer, rep domain.RestRepository, p *types.Path, exp []*StockState, expSkipped []*SkippedNode)
+	}{
+		{
+			Name: "Simple Product should skip ignored nearest warehouse",
+			Arrange: func() (ctx context.Context, prd DeliveryItemer, rep domain.RestRepository, p *types.Path, exp []*StockState, expSkipped []*SkippedNode) {
+				ctrl := gomock.NewController(t)
+				ctx = context.Background()
+				prdID := *guid.New()
+				filialID := *guid.New()
+				warehouseID1 := *guid.New()
+				warehouseID2 := *guid.New()
+				path := types.NewPath(2)
+				path.AddNode(warehouseID1)
+				path.AddNode(warehouseID2)
+				mockRep := mocks.NewMockRestRepository(ctrl)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prdID}).Return([]*domain.Rest{
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(10), ProductID: prdID, WarehouseID: warehouseID1},
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(10), ProductID: prdID, WarehouseID: warehouseID2},
+				}, nil)
+				rep = mockRep
+				p = path
+				product := NewSimpleProduct(prdID, decimal.NewFromInt(3), false, Nearest, filialID)
+				product.IgnoredNodes = []guid.Guid{warehouseID1}
+				prd = product
+				exp = []*StockState{
+					{ProductID: prdID, Quantity: decimal.NewFromInt(3), WarehouseID: &warehouseID2},
+				}
+				expSkipped = []*SkippedNode{
+					{WarehouseID: warehouseID1, ProductID: &prdID, Reason: SkipReasonIgnored},
+				}
+				return
+			},
+		},
+		{
+			Name: "Composite Product should not be gathered at a warehouse ignored by one of its products",
+			Arrange: func() (ctx context.Context, prd DeliveryItemer, rep domain.RestRepository, p *types.Path, exp []*StockState, expSkipped []*SkippedNode) {
+				ctrl := gomock.NewController(t)
+				ctx = context.Background()
+				filialID := *guid.New()
+				warehouseID1 := *guid.New()
+				warehouseID2 := *guid.New()
+				prod1 := NewSimpleProduct(*guid.New(), decimal.NewFromInt(1), false, Nearest, filialID)
+				prod2 := NewSimpleProduct(*guid.New(), decimal.NewFromInt(1), false, Nearest, filialID)
+				prod2.IgnoredNodes = []guid.Guid{warehouseID1}
+				path := types.NewPath(2)
+				path.AddNode(warehouseID1)
+				path.AddNode(warehouseID2)
+				mockRep := mocks.NewMockRestRepository(ctrl)
+				mockRep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prod1.ProductID, prod2.ProductID}).Return([]*domain.Rest{
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(5), ProductID: prod1.ProductID, WarehouseID: warehouseID1},
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(5), ProductID: prod2.ProductID, WarehouseID: warehouseID1},
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(5), ProductID: prod1.ProductID, WarehouseID: warehouseID2},
+					{RestID: *guid.New(), FilialID: &filialID, Quantity: decimal.NewFromInt(5), ProductID: prod2.ProductID, WarehouseID: warehouseID2},
+				}, nil)
+				rep = mockRep
+				p = path
+				prd = NewCompositeProduct([]*SimpleProduct{prod1, prod2}, Nearest, filialID)
+				exp = []*StockState{
+					{ProductID: prod1.ProductID, Quantity: decimal.NewFromInt(1), WarehouseID: &warehouseID2},
+					{ProductID: prod2.ProductID, Quantity: decimal.NewFromInt(1), WarehouseID: &warehouseID2},
+				}
+				expSkipped = []*SkippedNode{
+					{WarehouseID: warehouseID1, ProductID: &prod2.ProductID, Reason: SkipReasonIgnored},
+				}
+				return
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctx, prd, rep, p, exp, expSkipped := tt.Arrange()
+			sut, skipped, err := prd.Find(ctx, rep, p)
+			assert.NoError(t, err)
+			assert.Equal(t, exp, sut)
+			assert.Equal(t, expSkipped, skipped)
+		})
+	}
+}
+
+func TestCheapest(t *testing.T) {
+	filialID := *guid.New()
+	prdID := *guid.New()
+	w := []guid.Guid{*guid.New(), *guid.New(), *guid.New()}
+	newCostPath := func(costs ...types.NodeCost) *types.Path {
+		path := types.NewPath(len(costs))
+		for i, cost := range costs {
+			path.AddNodeWithCost(w[i], cost)
+		}
+		return path
+	}
+	cases := []struct {
+		Name       string
+		Path       *types.Path
+		Rests      []*domain.Rest
+		Product    func() DeliveryItemer
+		Exp        []*StockState
+		ExpSkipped []*SkippedNode
+	}{
+		{
+			Name: "Simple Product should be taken from the cheapest warehouses first",
+			Path: newCostPath(
+				types.NodeCost{Cost: decimal.NewFromInt(5)},
+				types.NodeCost{Cost: decimal.NewFromInt(1)},
+				types.NodeCost{Cost: decimal.NewFromInt(1)},
+			),
+			Rests: []*domain.Rest{
+				newRest(filialID, w[0], prdID, 10),
+				newRest(filialID, w[1], prdID, 2),
+				newRest(filialID, w[2], prdID, 10),
+			},
+			Product: func() DeliveryItemer {
+				return NewSimpleProduct(prdID, decimal.NewFromInt(5), false, Cheapest, filialID)
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+				{ProductID: prdID, Quantity: decimal.NewFromInt(3), WarehouseID: &w[2]},
+			},
+			ExpSkipped: []*SkippedNode{},
+		},
+		{
+			Name: "Simple Product should take unpriced warehouses last",
+			Path: func() *types.Path {
+				path := types.NewPath(3)
+				path.AddNode(w[0])
+				path.AddNodeWithCost(w[1], types.NodeCost{Cost: decimal.NewFromInt(3)})
+				path.AddNodeWithCost(w[2], types.NodeCost{Cost: decimal.NewFromInt(1)})
+				return path
+			}(),
+			Rests: []*domain.Rest{
+				newRest(filialID, w[0], prdID, 10),
+				newRest(filialID, w[1], prdID, 2),
+				newRest(filialID, w[2], prdID, 2),
+			},
+			Product: func() DeliveryItemer {
+				return NewSimpleProduct(prdID, decimal.NewFromInt(5), false, Cheapest, filialID)
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), WarehouseID: &w[2]},
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+				{ProductID: prdID, Quantity: decimal.NewFromInt(1), WarehouseID: &w[0]},
+			},
+			ExpSkipped: []*SkippedNode{},
+		},
+		{
+			Name: "Simple Product should skip warehouses missing the deadline",
+			Path: newCostPath(
+				types.NodeCost{Cost: decimal.NewFromInt(1), LeadTime: 96 * time.Hour},
+				types.NodeCost{Cost: decimal.NewFromInt(3), LeadTime: 24 * time.Hour},
+				types.NodeCost{Cost: decimal.NewFromInt(2), LeadTime: 48 * time.Hour},
+			),
+			Rests: []*domain.Rest{
+				newRest(filialID, w[0], prdID, 10),
+				newRest(filialID, w[1], prdID, 10),
+				newRest(filialID, w[2], prdID, 1),
+			},
+			Product: func() DeliveryItemer {
+				product := NewSimpleProduct(prdID, decimal.NewFromInt(3), false, Cheapest, filialID)
+				product.Deadline = 48 * time.Hour
+				return product
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(1), WarehouseID: &w[2]},
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), WarehouseID: &w[1]},
+			},
+			ExpSkipped: []*SkippedNode{
+				{WarehouseID: w[0], ProductID: &prdID, Reason: SkipReasonLeadTime},
+			},
+		},
+		{
+			Name: "Composite Product should be gathered at the cheapest warehouse holding all of it",
+			Path: newCostPath(
+				types.NodeCost{Cost: decimal.NewFromInt(4)},
+				types.NodeCost{Cost: decimal.NewFromInt(1)},
+				types.NodeCost{Cost: decimal.NewFromInt(2)},
+			),
+			Rests: []*domain.Rest{
+				newRest(filialID, w[0], prdID, 5),
+				newRest(filialID, w[1], prdID, 1),
+				newRest(filialID, w[2], prdID, 5),
+			},
+			Product: func() DeliveryItemer {
+				return NewCompositeProduct([]*SimpleProduct{
+					NewSimpleProduct(prdID, decimal.NewFromInt(2), false, Nearest, filialID),
+				}, Cheapest, filialID)
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), WarehouseID: &w[2]},
+			},
+			ExpSkipped: []*SkippedNode{},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			rep := mocks.NewMockRestRepository(ctrl)
+			rep.EXPECT().GetMany(ctx, filialID, tt.Path.Nodes(), []guid.Guid{prdID}).Return(tt.Rests, nil)
+
+			states, skipped, err := tt.Product().Find(ctx, rep, tt.Path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.Exp, states)
+			assert.Equal(t, tt.ExpSkipped, skipped)
+		})
+	}
+}
+
+func TestOverReservedRest(t *testing.T) {
+	filialID := *guid.New()
+	prdID := *guid.New()
+	w, path := newWarehouses(2)
+	overReserved := newRest(filialID, w[0], prdID, 2)
+	overReserved.Reserved = decimal.NewFromInt(5)
+	cases := []struct {
+		Name    string
+		Product func() DeliveryItemer
+		Exp     []*StockState
+	}{
+		{
+			Name: "Simple Product should pass a warehouse reserved beyond its quantity",
+			Product: func() DeliveryItemer {
+				return NewSimpleProduct(prdID, decimal.NewFromInt(3), false, Nearest, filialID)
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(1), WarehouseID: &w[1]},
+				{ProductID: prdID, Quantity: decimal.NewFromInt(2), Produce: true},
+			},
+		},
+		{
+			Name: "Composite Product should not be gathered at a warehouse reserved beyond its quantity",
+			Product: func() DeliveryItemer {
+				return NewCompositeProduct([]*SimpleProduct{
+					NewSimpleProduct(prdID, decimal.NewFromInt(1), false, Nearest, filialID),
+				}, Nearest, filialID)
+			},
+			Exp: []*StockState{
+				{ProductID: prdID, Quantity: decimal.NewFromInt(1), WarehouseID: &w[1]},
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			rep := mocks.NewMockRestRepository(ctrl)
+			rep.EXPECT().GetMany(ctx, filialID, path.Nodes(), []guid.Guid{prdID}).Return([]*domain.Rest{
+				overReserved,
+				newRest(filialID, w[1], prdID, 1),
+			}, nil)
+
+			states, _, err := tt.Product().Find(ctx, rep, path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.Exp, states)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+type restKey struct {
+	filialID    guid.Guid
+	warehouseID guid.Guid
+	productID   guid.Guid
+}
+
+// rests is an in-memory snapshot of the rests a strategy may look at, so the
+// path walk does not go to the repository for every node.
+type rests map[restKey]decimal.Decimal
+
+// prefetchRests loads rests of the requested products on every node of the
+// path with one repository call per filial.
+func prefetchRests(ctx context.Context, restRepository domain.RestRepository, path *types.Path, products ...*SimpleProduct) (rests, error) {
+	byFilial := make(map[guid.Guid][]guid.Guid)
+	filials := make([]guid.Guid, 0, 1)
+	for _, product := range products {
+		if _, ok := byFilial[product.FilialID]; !ok {
+			filials = append(filials, product.FilialID)
+		}
+		byFilial[product.FilialID] = append(byFilial[product.FilialID], product.ProductID)
+	}
+	result := make(rests)
+	for _, filialID := range filials {
+		items, err := restRepository.GetMany(ctx, filialID, path.Nodes(), byFilial[filialID])
+		if err != nil {
+			return nil, err
+		}
+		for _, rest := range items {
+			result[restKey{filialID: filialID, warehouseID: rest.WarehouseID, productID: rest.ProductID}] = rest.Available()
+		}
+	}
+	return result, nil
+}
+
+// quantity returns the available quantity, which is zero for rests that do
+// not exist.
+func (r rests) quantity(filialID, warehouseID, productID guid.Guid) decimal.Decimal {
+	return r[restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}]
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+var ErrNegativeRest = errors.New("rest cannot become negative")
+
+// Movement is a single signed change of a rest caused by a document. The
+// ledger is append-only, a wrong movement is corrected by a compensating one.
+// FilialID is nil for rests without a filial.
+type Movement struct {
+	MovementID    guid.Guid
+	FilialID      *guid.Guid
+	WarehouseID   guid.Guid
+	ProductID     guid.Guid
+	IntegrationID *guid.Guid
+	Delta         decimal.Decimal
+	Reason        MovementReason
+	DocumentID    guid.Guid
+	CreatedAt     time.Time
+}
+
+type MovementReason int
+
+const (
+	MovementReasonRECEIPT MovementReason = iota
+	MovementReasonSHIPMENT
+	MovementReasonWRITEOFF
+	MovementReasonTRANSFERIN
+	MovementReasonTRANSFEROUT
+	MovementReasonSYNC
+)
+
+func (mr MovementReason) String() string {
+	return [...]string{"RECEIPT", "SHIPMENT", "WRITE_OFF", "TRANSFER_IN", "TRANSFER_OUT", "SYNC"}[mr]
+}
+
+type LedgerRepository interface {
+	Append(ctx context.Context, movement *Movement) error
+
+	// RestAsOf reconstructs the rest from the movements recorded up to and
+	// including at. A nil filialID addresses the rest without a filial. The
+	// rest is zero when no movement precedes at. Reserved is not reconstructed
+	// and is always zero.
+	RestAsOf(ctx context.Context, filialID *guid.Guid, warehouseID guid.Guid, productID guid.Guid, at time.Time) (*Rest, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+// OutboxEvent is an event stored in the same transaction as the change it
+// describes and published later by the relay. Position orders the events of
+// a key, consumers must tolerate an event being delivered more than once and use
+// EventID to drop duplicates.
+type OutboxEvent struct {
+	Position  int64
+	EventID   guid.Guid
+	Topic     string
+	Key       string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// NewOutboxEvent encodes the payload as JSON. Key groups events that must be
+// consumed in order, e.g. the events of one order or one document.
+func NewOutboxEvent(topic string, key string, payload any, now time.Time) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		EventID:   *guid.New(),
+		Topic:     topic,
+		Key:       key,
+		Payload:   data,
+		CreatedAt: now,
+	}, nil
+}
+
+type OutboxRepository interface {
+	// Add stores the event and sets its position. It must be called in the
+	// transaction that makes the change the event describes. Transactions
+	// adding events of the same key are serialized from the first Add until
+	// they end, so the positions of a key become visible in order.
+	Add(ctx context.Context, event *OutboxEvent) error
+
+	// Pending returns up to limit unpublished events in position order and
+	// keeps them locked until the transaction ends. Events locked by another
+	// relay are skipped, and so are the events of a key whose earlier event
+	// is left to another relay, so concurrent relays share the work without
+	// publishing a key out of order.
+	Pending(ctx context.Context, limit int) ([]*OutboxEvent, error)
+
+	// MarkPublished marks the events at the positions as published.
+	MarkPublished(ctx context.Context, positions []int64, at time.Time) error
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock to reserve")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExpired  = errors.New("reservation expired")
+	ErrAlreadyReserved     = errors.New("order already has reservations")
+)
+
+type Reservation struct {
+	ReservationID guid.Guid
+	OrderID       guid.Guid
+	FilialID      guid.Guid
+	ProductID     guid.Guid
+	WarehouseID   guid.Guid
+	Quantity      decimal.Decimal
+	Status        ReservationStatus
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+type ReservationStatus int
+
+const (
+	ReservationStatusACTIVE ReservationStatus = iota
+	ReservationStatusCONFIRMED
+	ReservationStatusRELEASED
+	ReservationStatusEXPIRED
+	ReservationStatusFULFILLED
+)
+
+func (rs ReservationStatus) String() string {
+	return [...]string{"ACTIVE", "CONFIRMED", "RELEASED", "EXPIRED", "FULFILLED"}[rs]
+}
+
+type ReservationRepository interface {
+	// Hold stores an active reservation if the rest minus the quantity already
+	// held covers it, and returns ErrInsufficientStock otherwise. The rest row
+	// must be locked for update in the same transaction beforehand.
+	Hold(ctx context.Context, reservation *Reservation) error
+
+	GetByOrder(ctx context.Context, orderID guid.Guid) ([]*Reservation, error)
+
+	// LockOrder serializes the reservation changes of the order until the
+	// transaction ends, so concurrent reserves, confirms, releases and
+	// shipments of one order see each other's reservations.
+	LockOrder(ctx context.Context, orderID guid.Guid) error
+
+	// SetStatus moves the order's reservations that are in the from status to
+	// the to status and returns how many were changed.
+	SetStatus(ctx context.Context, orderID guid.Guid, from ReservationStatus, to ReservationStatus) (int64, error)
+
+	// Fulfil marks the confirmed reservations the order holds at the warehouse
+	// as fulfilled and returns them. Fulfilled reservations no longer hold
+	// stock, so the shipment taking the stock out is not blocked by them.
+	Fulfil(ctx context.Context, orderID guid.Guid, warehouseID guid.Guid) ([]*Reservation, error)
+
+	// ExpireBatch marks up to limit active reservations that are past their
+	// expiry as expired and returns them. Rows locked by other transactions
+	// are skipped.
+	ExpireBatch(ctx context.Context, limit int) ([]*Reservation, error)
+}
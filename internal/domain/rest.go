@@ -2,20 +2,72 @@ package domain
 
 import (
 	"context"
+	"errors"
 
 	"github.com/beevik/guid"
 	"github.com/shopspring/decimal"
 )
 
+var ErrRestNotFound = errors.New("rest not found")
+
 type Rest struct {
 	RestID        guid.Guid
 	FilialID      *guid.Guid
 	IntegrationID *guid.Guid
 	Quantity      decimal.Decimal
+	Reserved      decimal.Decimal
 	ProductID     guid.Guid
 	WarehouseID   guid.Guid
 }
 
+// Available is the part of the rest that is not held by active or confirmed
+// reservations. It is never negative, even when more is reserved than held.
+func (r *Rest) Available() decimal.Decimal {
+	available := r.Quantity.Sub(r.Reserved)
+	if available.IsNegative() {
+		return decimal.Zero
+	}
+	return available
+}
+
+// RestFilter narrows a rest scan. Empty fields do not filter.
+type RestFilter struct {
+	FilialID     *guid.Guid
+	WarehouseIDs []guid.Guid
+	ProductIDs   []guid.Guid
+	NonZero      bool
+}
+
 type RestRepository interface {
 	Get(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid) (*Rest, error)
+
+	GetMany(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*Rest, error)
+
+	// GetPairs returns the rests of the filial whose warehouse and product
+	// are one of the pairs warehouseIDs[i], productIDs[i]. Unlike GetMany it
+	// does not read, or lock, the other combinations.
+	GetPairs(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*Rest, error)
+
+	// Each streams the rests matching the filter ordered by filial, warehouse
+	// and product, stopping at the first error returned by fn.
+	Each(ctx context.Context, filter RestFilter, fn func(rest *Rest) error) error
+
+	// Adjust adds delta to the rest, creating it when it does not exist yet,
+	// and returns ErrNegativeRest if the result would be below zero or
+	// ErrInsufficientStock if it would be below the held reservations.
+	Adjust(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid, delta decimal.Decimal) (*Rest, error)
+
+	// Sync sets the rest identified by the snapshot integration id to the
+	// snapshot quantity, creating the rest when it does not exist yet. A rest
+	// of the same key without an integration id takes over the integration
+	// id; any other key conflict returns ErrIntegrationKeyMismatch.
+	Sync(ctx context.Context, rest *SnapshotRest) (*SyncResult, error)
+
+	// ZeroMissing zeroes the non-zero ERP rests of the filial (of all filials
+	// when filialID is nil) whose integration id is not in keep.
+	ZeroMissing(ctx context.Context, filialID *guid.Guid, keep []guid.Guid) ([]*SyncResult, error)
+
+	// WithLock returns a repository whose reads lock the returned rest rows
+	// until the surrounding transaction ends.
+	WithLock(lock RowLock) RestRepository
 }
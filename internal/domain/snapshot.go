@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrDuplicateIntegrationID = errors.New("integration id occurs more than once in the snapshot")
+	ErrIntegrationKeyMismatch = errors.New("integration id and rest key belong to different rests")
+)
+
+// RestSnapshot is a set of rests sent by the ERP. A full snapshot is the
+// whole truth for its filial (or for all filials when FilialID is nil), so
+// ERP rests missing from it are zeroed. A delta snapshot only touches the
+// rests it contains.
+type RestSnapshot struct {
+	DocumentID guid.Guid
+	Full       bool
+	FilialID   *guid.Guid
+	Rests      []*SnapshotRest
+}
+
+type SnapshotRest struct {
+	IntegrationID guid.Guid
+	FilialID      *guid.Guid
+	WarehouseID   guid.Guid
+	ProductID     guid.Guid
+	Quantity      decimal.Decimal
+}
+
+// Matches reports whether the rest can take the snapshot rest: it has the same
+// filial, warehouse and product and either the same integration id or none.
+func (sr *SnapshotRest) Matches(rest *Rest) bool {
+	if rest.IntegrationID != nil && *rest.IntegrationID != sr.IntegrationID {
+		return false
+	}
+	sameFilial := (rest.FilialID == nil) == (sr.FilialID == nil) && (rest.FilialID == nil || *rest.FilialID == *sr.FilialID)
+	return sameFilial && rest.WarehouseID == sr.WarehouseID && rest.ProductID == sr.ProductID
+}
+
+type SyncOutcome int
+
+const (
+	SyncOutcomeUNCHANGED SyncOutcome = iota
+	SyncOutcomeINSERTED
+	SyncOutcomeUPDATED
+	SyncOutcomeREMOVED
+)
+
+func (so SyncOutcome) String() string {
+	return [...]string{"UNCHANGED", "INSERTED", "UPDATED", "REMOVED"}[so]
+}
+
+// SyncResult is the effect of a snapshot on a single rest. Rest is nil for
+// unchanged rests.
+type SyncResult struct {
+	Outcome  SyncOutcome
+	Rest     *Rest
+	Previous decimal.Decimal
+}
+
+// Delta is the signed change the sync made to the rest quantity.
+func (sr *SyncResult) Delta() decimal.Decimal {
+	if sr.Rest == nil {
+		return decimal.Zero
+	}
+	return sr.Rest.Quantity.Sub(sr.Previous)
+}
+
+// RestDiff is a rest a bulk import would insert (Previous is nil) or change.
+type RestDiff struct {
+	IntegrationID guid.Guid
+	FilialID      *guid.Guid
+	WarehouseID   guid.Guid
+	ProductID     guid.Guid
+	Previous      *decimal.Decimal
+	Quantity      decimal.Decimal
+}
+
+// SnapshotRestSource returns the next snapshot rest, or nil when there are
+// no more.
+type SnapshotRestSource func() (*SnapshotRest, error)
+
+// SnapshotRestsOf returns a source of the rests.
+func SnapshotRestsOf(rests []*SnapshotRest) SnapshotRestSource {
+	return func() (*SnapshotRest, error) {
+		if len(rests) == 0 {
+			return nil, nil
+		}
+		rest := rests[0]
+		rests = rests[1:]
+		return rest, nil
+	}
+}
+
+// RestStagingRepository loads rests into a transaction scoped staging table
+// and merges them into the rests by integration id, attaching it to a rest of
+// the same key that has none yet. Diff and Merge lock the matched rests until
+// the transaction ends and return ErrIntegrationKeyMismatch when the keys
+// cannot be reconciled. It must be used inside UnitOfWork.Begin.
+type RestStagingRepository interface {
+	// Stage streams the rests into the staging table and returns how many
+	// were staged, or ErrDuplicateIntegrationID when an integration id is
+	// staged twice.
+	Stage(ctx context.Context, next SnapshotRestSource) (int64, error)
+
+	Diff(ctx context.Context) ([]*RestDiff, error)
+
+	// Merge applies the diff and records it in the ledger as SYNC movements of
+	// the document.
+	Merge(ctx context.Context, documentID guid.Guid, at time.Time) (int64, error)
+}
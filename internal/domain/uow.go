@@ -7,5 +7,59 @@ import (
 type UnitOfWork interface {
 	Rest() RestRepository
 
-	Begin(ctx context.Context, fn func(work UnitOfWork) error) error
+	Warehouse() WarehouseRepository
+
+	Reservation() ReservationRepository
+
+	Ledger() LedgerRepository
+
+	Staging() RestStagingRepository
+
+	Outbox() OutboxRepository
+
+	// Begin runs fn in a transaction that is committed when fn returns nil and
+	// rolled back otherwise. Calling Begin on the unit of work passed to fn
+	// starts a nested transaction on a savepoint. Options only apply to the
+	// outermost transaction.
+	Begin(ctx context.Context, fn func(work UnitOfWork) error, opts ...TxOption) error
+}
+
+type IsolationLevel int
+
+const (
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+func (il IsolationLevel) String() string {
+	return [...]string{"DEFAULT", "READ_COMMITTED", "REPEATABLE_READ", "SERIALIZABLE"}[il]
+}
+
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+}
+
+type TxOption func(*TxOptions)
+
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+func ReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+func NewTxOptions(opts ...TxOption) TxOptions {
+	var options TxOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
 }
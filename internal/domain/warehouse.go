@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/beevik/guid"
+import (
+	"context"
+
+	"github.com/beevik/guid"
+)
 
 type Warehouse struct {
 	WarehouseID     guid.Guid
@@ -22,3 +26,7 @@ const (
 func (wt WarehouseType) String() string {
 	return [...]string{"FREE", "MAIN", "MAIN_WAREHOUSE", "SHOPPING_CENTER"}[wt]
 }
+
+type WarehouseRepository interface {
+	GetMany(ctx context.Context, warehouseIDs []guid.Guid) ([]*Warehouse, error)
+}
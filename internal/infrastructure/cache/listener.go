@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/beevik/guid"
+)
+
+type restChanged struct {
+	FilialID    *string `json:"filial_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	ProductID   string  `json:"product_id"`
+}
+
+// HandleNotification invalidates the rest named by a rest_changed payload.
+// Register it on a notify.Listener for notify.RestChangedChannel together
+// with Purge on subscribe, as notifications are lost while the listener is
+// disconnected.
+func (c *RestCache) HandleNotification(_ context.Context, payload string) {
+	var changed restChanged
+	if err := json.Unmarshal([]byte(payload), &changed); err != nil {
+		slog.Warn("rest cache got malformed notification", "payload", payload, "error", err)
+		c.Purge()
+		return
+	}
+	warehouseID, err := guid.ParseString(changed.WarehouseID)
+	if err != nil {
+		c.Purge()
+		return
+	}
+	productID, err := guid.ParseString(changed.ProductID)
+	if err != nil {
+		c.Purge()
+		return
+	}
+	var filialID guid.Guid
+	if changed.FilialID != nil {
+		parsed, err := guid.ParseString(*changed.FilialID)
+		if err != nil {
+			c.Purge()
+			return
+		}
+		filialID = *parsed
+	}
+	c.Invalidate(filialID, *warehouseID, *productID)
+}
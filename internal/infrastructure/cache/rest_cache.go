@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+type restKey struct {
+	filialID    guid.Guid
+	warehouseID guid.Guid
+	productID   guid.Guid
+}
+
+// RestCache is a read-through cache of rests for reads that do not need to
+// be transactional. A nil cached rest records that the rest does not exist.
+// Locking reads and writes go to the wrapped repository.
+type RestCache struct {
+	rests   domain.RestRepository
+	entries *lru[restKey, *domain.Rest]
+	// generation changes on every invalidation, so a read that raced with one
+	// does not put what it read into the cache.
+	generation    atomic.Uint64
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+}
+
+func NewRestCache(rests domain.RestRepository, capacity int, ttl time.Duration) *RestCache {
+	return &RestCache{rests: rests, entries: newLRU[restKey, *domain.Rest](capacity, ttl)}
+}
+
+func (c *RestCache) Get(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid) (*domain.Rest, error) {
+	key := restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}
+	if rest, ok := c.entries.get(key); ok {
+		c.hits.Add(1)
+		return found(rest)
+	}
+	c.misses.Add(1)
+	generation := c.generation.Load()
+	rest, err := c.rests.Get(ctx, filialID, warehouseID, productID)
+	if err != nil && !errors.Is(err, domain.ErrRestNotFound) {
+		return nil, err
+	}
+	c.put(generation, key, rest)
+	return found(rest)
+}
+
+// GetMany serves the cached rests and reads the missing ones in one call to
+// the wrapped repository, asking for the warehouses and products that missed.
+func (c *RestCache) GetMany(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	cached := make(map[restKey]*domain.Rest, len(warehouseIDs)*len(productIDs))
+	var missingWarehouses, missingProducts []guid.Guid
+	missing := 0
+	for _, warehouseID := range warehouseIDs {
+		for _, productID := range productIDs {
+			key := restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}
+			rest, ok := c.entries.get(key)
+			if ok {
+				cached[key] = rest
+				continue
+			}
+			missing++
+			missingWarehouses = appendMissing(missingWarehouses, warehouseID)
+			missingProducts = appendMissing(missingProducts, productID)
+		}
+	}
+	c.hits.Add(uint64(len(warehouseIDs)*len(productIDs) - missing))
+	if missing > 0 {
+		c.misses.Add(uint64(missing))
+		generation := c.generation.Load()
+		fetched, err := c.rests.GetMany(ctx, filialID, missingWarehouses, missingProducts)
+		if err != nil {
+			return nil, err
+		}
+		byKey := make(map[restKey]*domain.Rest, len(fetched))
+		for _, rest := range fetched {
+			byKey[restKey{filialID: filialID, warehouseID: rest.WarehouseID, productID: rest.ProductID}] = rest
+		}
+		for _, warehouseID := range missingWarehouses {
+			for _, productID := range missingProducts {
+				key := restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}
+				c.put(generation, key, byKey[key])
+				cached[key] = byKey[key]
+			}
+		}
+	}
+	rests := make([]*domain.Rest, 0, len(cached))
+	for _, warehouseID := range warehouseIDs {
+		for _, productID := range productIDs {
+			key := restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}
+			if rest := cached[key]; rest != nil {
+				rests = append(rests, clone(rest))
+				delete(cached, key)
+			}
+		}
+	}
+	return rests, nil
+}
+
+func appendMissing(ids []guid.Guid, id guid.Guid) []guid.Guid {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// GetPairs is only used to lock rests and is not cached.
+func (c *RestCache) GetPairs(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	return c.rests.GetPairs(ctx, filialID, warehouseIDs, productIDs)
+}
+
+func (c *RestCache) Each(ctx context.Context, filter domain.RestFilter, fn func(rest *domain.Rest) error) error {
+	return c.rests.Each(ctx, filter, fn)
+}
+
+func (c *RestCache) Adjust(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid, delta decimal.Decimal) (*domain.Rest, error) {
+	defer c.Invalidate(filialID, warehouseID, productID)
+	return c.rests.Adjust(ctx, filialID, warehouseID, productID, delta)
+}
+
+func (c *RestCache) Sync(ctx context.Context, rest *domain.SnapshotRest) (*domain.SyncResult, error) {
+	var filialID guid.Guid
+	if rest.FilialID != nil {
+		filialID = *rest.FilialID
+	}
+	defer c.Invalidate(filialID, rest.WarehouseID, rest.ProductID)
+	return c.rests.Sync(ctx, rest)
+}
+
+func (c *RestCache) ZeroMissing(ctx context.Context, filialID *guid.Guid, keep []guid.Guid) ([]*domain.SyncResult, error) {
+	defer c.Purge()
+	return c.rests.ZeroMissing(ctx, filialID, keep)
+}
+
+// WithLock bypasses the cache, a locking read has to see the current row.
+func (c *RestCache) WithLock(lock domain.RowLock) domain.RestRepository {
+	if lock.Strength == domain.LockNone {
+		return c
+	}
+	return c.rests.WithLock(lock)
+}
+
+func (c *RestCache) Invalidate(filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid) {
+	c.generation.Add(1)
+	c.invalidations.Add(1)
+	c.entries.remove(restKey{filialID: filialID, warehouseID: warehouseID, productID: productID})
+}
+
+// Purge drops every cached rest, e.g. when invalidations may have been missed.
+func (c *RestCache) Purge() {
+	c.generation.Add(1)
+	c.invalidations.Add(1)
+	c.entries.purge()
+}
+
+func (c *RestCache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		Size:          c.entries.len(),
+	}
+}
+
+// put caches what was read at the generation unless an invalidation happened
+// since. Invalidations change the generation before removing the entry, so
+// checking it under the lru lock either skips the store or lets the removal
+// drop it.
+func (c *RestCache) put(generation uint64, key restKey, rest *domain.Rest) {
+	c.entries.putIf(key, clone(rest), func() bool {
+		return c.generation.Load() == generation
+	})
+}
+
+// found hands out a copy, so callers cannot change the cached rest.
+func found(rest *domain.Rest) (*domain.Rest, error) {
+	if rest == nil {
+		return nil, domain.ErrRestNotFound
+	}
+	return clone(rest), nil
+}
+
+func clone(rest *domain.Rest) *domain.Rest {
+	if rest == nil {
+		return nil
+	}
+	c := *rest
+	return &c
+}
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/mocks"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestCacheGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	missingID := *guid.New()
+	rest := &domain.Rest{RestID: *guid.New(), Quantity: decimal.NewFromInt(3), WarehouseID: warehouseID, ProductID: productID}
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Get(ctx, filialID, warehouseID, productID).Return(rest, nil).Times(2)
+	rests.EXPECT().Get(ctx, filialID, warehouseID, missingID).Return(nil, domain.ErrRestNotFound)
+	sut := NewRestCache(rests, 10, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		got, err := sut.Get(ctx, filialID, warehouseID, productID)
+		require.NoError(t, err)
+		assert.Equal(t, rest, got)
+		assert.NotSame(t, rest, got)
+
+		_, err = sut.Get(ctx, filialID, warehouseID, missingID)
+		assert.ErrorIs(t, err, domain.ErrRestNotFound)
+	}
+	sut.Invalidate(filialID, warehouseID, productID)
+	_, err := sut.Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 3, Invalidations: 1, Size: 2}, sut.Stats())
+}
+
+func TestRestCacheGetMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseIDs := []guid.Guid{*guid.New(), *guid.New()}
+	productIDs := []guid.Guid{*guid.New()}
+	rest := &domain.Rest{Quantity: decimal.NewFromInt(1), WarehouseID: warehouseIDs[1], ProductID: productIDs[0]}
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().GetMany(ctx, filialID, warehouseIDs, productIDs).Return([]*domain.Rest{rest}, nil)
+	sut := NewRestCache(rests, 10, time.Minute)
+
+	first, err := sut.GetMany(ctx, filialID, warehouseIDs, productIDs)
+	require.NoError(t, err)
+	second, err := sut.GetMany(ctx, filialID, warehouseIDs, productIDs)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*domain.Rest{rest}, first)
+	assert.Equal(t, []*domain.Rest{rest}, second)
+	_, err = sut.Get(ctx, filialID, warehouseIDs[0], productIDs[0])
+	assert.ErrorIs(t, err, domain.ErrRestNotFound)
+	assert.Equal(t, uint64(3), sut.Stats().Hits)
+	assert.Equal(t, uint64(2), sut.Stats().Misses)
+}
+
+func TestRestCacheGetManyFetchesMisses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	cachedID := *guid.New()
+	missingID := *guid.New()
+	cached := &domain.Rest{Quantity: decimal.NewFromInt(1), WarehouseID: warehouseID, ProductID: cachedID}
+	fetched := &domain.Rest{Quantity: decimal.NewFromInt(2), WarehouseID: warehouseID, ProductID: missingID}
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Get(ctx, filialID, warehouseID, cachedID).Return(cached, nil)
+	rests.EXPECT().GetMany(ctx, filialID, []guid.Guid{warehouseID}, []guid.Guid{missingID}).Return([]*domain.Rest{fetched}, nil)
+	sut := NewRestCache(rests, 10, time.Minute)
+	_, err := sut.Get(ctx, filialID, warehouseID, cachedID)
+	require.NoError(t, err)
+
+	got, err := sut.GetMany(ctx, filialID, []guid.Guid{warehouseID}, []guid.Guid{missingID, cachedID})
+
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Rest{fetched, cached}, got)
+	assert.Equal(t, Stats{Hits: 1, Misses: 2, Size: 2}, sut.Stats())
+}
+
+func TestRestCacheDropsReadRacingInvalidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	rests := mocks.NewMockRestRepository(ctrl)
+	var sut *RestCache
+	rests.EXPECT().Get(ctx, filialID, warehouseID, productID).DoAndReturn(func(context.Context, guid.Guid, guid.Guid, guid.Guid) (*domain.Rest, error) {
+		sut.Invalidate(filialID, warehouseID, productID)
+		return &domain.Rest{Quantity: decimal.NewFromInt(1)}, nil
+	}).Times(2)
+	sut = NewRestCache(rests, 10, time.Minute)
+
+	_, err := sut.Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+	_, err = sut.Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), sut.Stats().Misses)
+}
+
+func TestRestCacheLockingReadBypassesCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	locked := mocks.NewMockRestRepository(ctrl)
+	rests := mocks.NewMockRestRepository(ctrl)
+	lock := domain.RowLock{Strength: domain.LockForUpdate}
+	rests.EXPECT().WithLock(lock).Return(locked)
+	sut := NewRestCache(rests, 10, time.Minute)
+
+	assert.Same(t, sut, sut.WithLock(domain.RowLock{}))
+	assert.Same(t, locked, sut.WithLock(lock))
+}
+
+func TestRestCacheInvalidatePayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	rests := mocks.NewMockRestRepository(ctrl)
+	rests.EXPECT().Get(ctx, filialID, warehouseID, productID).Return(&domain.Rest{}, nil).Times(2)
+	sut := NewRestCache(rests, 10, time.Minute)
+	_, _ = sut.Get(ctx, filialID, warehouseID, productID)
+
+	sut.HandleNotification(ctx, `{"table":"reservation","op":"UPDATE","filial_id":"`+filialID.String()+
+		`","warehouse_id":"`+warehouseID.String()+`","product_id":"`+productID.String()+`","old_quantity":null,"new_quantity":null}`)
+	_, _ = sut.Get(ctx, filialID, warehouseID, productID)
+
+	assert.Equal(t, uint64(2), sut.Stats().Misses)
+}
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	c := newLRU[int, string](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.put(1, "a")
+	c.put(2, "b")
+	_, _ = c.get(1)
+	c.put(3, "c")
+
+	_, ok := c.get(2)
+	assert.False(t, ok, "least recently used entry is evicted")
+	value, ok := c.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get(3)
+	assert.False(t, ok, "expired entry is missing")
+	assert.Equal(t, 1, c.len())
+}
+
+func TestRestCacheInvalidationDuringPut(t *testing.T) {
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	key := restKey{filialID: filialID, warehouseID: warehouseID, productID: productID}
+	sut := NewRestCache(nil, 10, time.Minute)
+	invalidated := make(chan struct{})
+
+	sut.entries.putIf(key, &domain.Rest{}, func() bool {
+		go func() {
+			sut.Invalidate(filialID, warehouseID, productID)
+			close(invalidated)
+		}()
+		return true
+	})
+	<-invalidated
+
+	_, ok := sut.entries.get(key)
+	assert.False(t, ok, "invalidation waits for the store and removes it")
+}
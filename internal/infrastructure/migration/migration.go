@@ -0,0 +1,233 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/shared/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// lockKey is the advisory lock held while migrating, so concurrently started
+// instances apply every migration once.
+const lockKey = 7_358_210_041
+
+const (
+	lockQuery        = `SELECT pg_advisory_xact_lock($1)`
+	createTableQuery = `CREATE TABLE IF NOT EXISTS public.schema_migration (
+					version integer PRIMARY KEY,
+					name text NOT NULL,
+					applied_at timestamptz NOT NULL DEFAULT now()
+				)`
+	appliedQuery = `SELECT version, applied_at FROM public.schema_migration`
+	insertQuery  = `INSERT INTO public.schema_migration (version, name) VALUES ($1, $2)`
+	deleteQuery  = `DELETE FROM public.schema_migration WHERE version = $1`
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	*Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         db.QueryExecutor
+	migrations []*Migration
+}
+
+func NewMigrator(db db.QueryExecutor) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies the pending migrations in version order and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var done []*Migration
+	err := m.locked(ctx, func(tx pgx.Tx, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := tx.Exec(ctx, insertQuery, migration.Version, migration.Name); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Down reverts up to steps of the most recently applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var done []*Migration
+	err := m.locked(ctx, func(tx pgx.Tx, applied map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := tx.Exec(ctx, deleteQuery, migration.Version); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	var statuses []*Status
+	err := m.locked(ctx, func(_ pgx.Tx, applied map[int]time.Time) error {
+		statuses = make([]*Status, len(m.migrations))
+		for i, migration := range m.migrations {
+			statuses[i] = &Status{Migration: migration}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				statuses[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// locked runs fn in a transaction holding the migration advisory lock. The
+// migrations are applied all or nothing as DDL is transactional in Postgres.
+func (m *Migrator) locked(ctx context.Context, fn func(tx pgx.Tx, applied map[int]time.Time) error) (err error) {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback(ctx))
+		}
+	}()
+	if _, err = tx.Exec(ctx, lockQuery, int64(lockKey)); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, createTableQuery); err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx, appliedQuery)
+	if err != nil {
+		return err
+	}
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version   int32
+			appliedAt time.Time
+		)
+		if err = rows.Scan(&version, &appliedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[int(version)] = appliedAt
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if err = fn(tx, applied); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// load reads migrations named <version>_<name>.up.sql and .down.sql.
+func load(fsys fs.FS) ([]*Migration, error) {
+	paths, err := fs.Glob(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, p := range paths {
+		base := path.Base(p)
+		name, direction, ok := cutDirection(base)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql suffix", base)
+		}
+		rawVersion, name, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>", base)
+		}
+		version, err := strconv.Atoi(rawVersion)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", base, err)
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %s: version %d is also named %s", base, version, migration.Name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down are required", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func cutDirection(base string) (string, string, bool) {
+	if name, ok := strings.CutSuffix(base, ".up.sql"); ok {
+		return name, "up", true
+	}
+	if name, ok := strings.CutSuffix(base, ".down.sql"); ok {
+		return name, "down", true
+	}
+	return "", "", false
+}
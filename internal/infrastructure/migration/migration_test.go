@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	migrations, err := load(files)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version)
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+	}
+}
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0010_b.up.sql":   {Data: []byte("up b")},
+		"sql/0010_b.down.sql": {Data: []byte("down b")},
+		"sql/0002_a.up.sql":   {Data: []byte("up a")},
+		"sql/0002_a.down.sql": {Data: []byte("down a")},
+	}
+
+	migrations, err := load(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, &Migration{Version: 2, Name: "a", Up: "up a", Down: "down a"}, migrations[0])
+	assert.Equal(t, 10, migrations[1].Version)
+}
+
+func TestLoadMissingDown(t *testing.T) {
+	_, err := load(fstest.MapFS{"sql/0001_a.up.sql": {Data: []byte("up")}})
+
+	assert.ErrorContains(t, err, "both up and down")
+}
@@ -0,0 +1,7 @@
+CREATE TABLE public.warehouse (
+    id               uuid     PRIMARY KEY,
+    type             smallint NOT NULL DEFAULT 0,
+    descriptor_group text     NOT NULL DEFAULT '',
+    rest_available   boolean  NOT NULL DEFAULT true,
+    pickup_only      boolean  NOT NULL DEFAULT false
+);
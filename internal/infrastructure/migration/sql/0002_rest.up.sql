@@ -0,0 +1,12 @@
+CREATE TABLE public.rest (
+    id             uuid    PRIMARY KEY,
+    filial_id      uuid,
+    integration_id uuid,
+    warehouse_id   uuid    NOT NULL,
+    product_id     uuid    NOT NULL,
+    quantity       numeric NOT NULL DEFAULT 0,
+    CONSTRAINT rest_quantity_non_negative CHECK (quantity >= 0)
+);
+
+CREATE UNIQUE INDEX rest_filial_warehouse_product_key ON public.rest (filial_id, warehouse_id, product_id);
+CREATE UNIQUE INDEX rest_integration_id_key ON public.rest (integration_id);
@@ -0,0 +1,16 @@
+-- status: 0 active, 1 confirmed, 2 released, 3 expired
+CREATE TABLE public.reservation (
+    id           uuid        PRIMARY KEY,
+    order_id     uuid        NOT NULL,
+    filial_id    uuid        NOT NULL,
+    warehouse_id uuid        NOT NULL,
+    product_id   uuid        NOT NULL,
+    quantity     numeric     NOT NULL CHECK (quantity > 0),
+    status       smallint    NOT NULL DEFAULT 0 CHECK (status BETWEEN 0 AND 3),
+    expires_at   timestamptz NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX reservation_order_id_idx ON public.reservation (order_id);
+CREATE INDEX reservation_held_idx ON public.reservation (filial_id, warehouse_id, product_id) WHERE status IN (0, 1);
+CREATE INDEX reservation_active_expires_at_idx ON public.reservation (expires_at) WHERE status = 0;
@@ -0,0 +1,2 @@
+DROP TABLE public.stock_movement;
+DROP FUNCTION public.stock_movement_append_only();
@@ -0,0 +1,32 @@
+-- reason: 0 receipt, 1 shipment, 2 write-off, 3 transfer in, 4 transfer out, 5 sync
+CREATE TABLE public.stock_movement (
+    id             uuid        PRIMARY KEY,
+    filial_id      uuid,
+    warehouse_id   uuid        NOT NULL,
+    product_id     uuid        NOT NULL,
+    integration_id uuid,
+    delta          numeric     NOT NULL,
+    reason         smallint    NOT NULL CHECK (reason BETWEEN 0 AND 5),
+    document_id    uuid        NOT NULL,
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX stock_movement_rest_created_at_idx ON public.stock_movement (filial_id, warehouse_id, product_id, created_at);
+CREATE INDEX stock_movement_document_id_idx ON public.stock_movement (document_id);
+
+-- The ledger is append-only, mistakes are fixed with compensating movements.
+CREATE FUNCTION public.stock_movement_append_only() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    RAISE EXCEPTION 'stock_movement is append-only, % is not allowed', TG_OP;
+END;
+$$;
+
+CREATE TRIGGER stock_movement_append_only
+    BEFORE UPDATE OR DELETE ON public.stock_movement
+    FOR EACH ROW EXECUTE FUNCTION public.stock_movement_append_only();
+
+CREATE TRIGGER stock_movement_no_truncate
+    BEFORE TRUNCATE ON public.stock_movement
+    FOR EACH STATEMENT EXECUTE FUNCTION public.stock_movement_append_only();
@@ -0,0 +1,3 @@
+DROP TRIGGER reservation_notify_rest_changed ON public.reservation;
+DROP TRIGGER rest_notify_changed ON public.rest;
+DROP FUNCTION public.notify_rest_changed();
@@ -0,0 +1,29 @@
+-- Notifies rest_changed with the rest key whenever a rest or a reservation
+-- held against it changes, so caches of rests can be invalidated.
+CREATE FUNCTION public.notify_rest_changed() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+DECLARE
+    changed record;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+    PERFORM pg_notify('rest_changed', json_build_object(
+        'filial_id', changed.filial_id,
+        'warehouse_id', changed.warehouse_id,
+        'product_id', changed.product_id
+    )::text);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER rest_notify_changed
+    AFTER INSERT OR UPDATE OR DELETE ON public.rest
+    FOR EACH ROW EXECUTE FUNCTION public.notify_rest_changed();
+
+CREATE TRIGGER reservation_notify_rest_changed
+    AFTER INSERT OR UPDATE OR DELETE ON public.reservation
+    FOR EACH ROW EXECUTE FUNCTION public.notify_rest_changed();
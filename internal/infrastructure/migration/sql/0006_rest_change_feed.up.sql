@@ -0,0 +1,62 @@
+-- Extends the rest_changed notification into the rest change feed. Besides
+-- the rest key a notification carries the table and the operation, and a rest
+-- change the old and the new quantity. The payload stays small whatever the
+-- row holds, and the identical notifications of a reservation key sent by
+-- one transaction are delivered once. Updates that change neither what the
+-- rest caches hold nor what reservations hold are not notified.
+CREATE OR REPLACE FUNCTION public.notify_rest_changed() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+DECLARE
+    changed      record;
+    old_quantity numeric;
+    new_quantity numeric;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'rest' THEN
+        IF TG_OP <> 'INSERT' THEN
+            old_quantity := OLD.quantity;
+        END IF;
+        IF TG_OP <> 'DELETE' THEN
+            new_quantity := NEW.quantity;
+        END IF;
+    END IF;
+    PERFORM pg_notify('rest_changed', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', TG_OP,
+        'filial_id', changed.filial_id,
+        'warehouse_id', changed.warehouse_id,
+        'product_id', changed.product_id,
+        'old_quantity', old_quantity,
+        'new_quantity', new_quantity
+    )::text);
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER rest_notify_changed ON public.rest;
+
+CREATE TRIGGER rest_notify_changed
+    AFTER INSERT OR DELETE ON public.rest
+    FOR EACH ROW EXECUTE FUNCTION public.notify_rest_changed();
+
+CREATE TRIGGER rest_notify_updated
+    AFTER UPDATE ON public.rest
+    FOR EACH ROW WHEN (OLD.quantity IS DISTINCT FROM NEW.quantity
+        OR OLD.integration_id IS DISTINCT FROM NEW.integration_id)
+    EXECUTE FUNCTION public.notify_rest_changed();
+
+DROP TRIGGER reservation_notify_rest_changed ON public.reservation;
+
+CREATE TRIGGER reservation_notify_rest_changed
+    AFTER INSERT OR DELETE ON public.reservation
+    FOR EACH ROW EXECUTE FUNCTION public.notify_rest_changed();
+
+CREATE TRIGGER reservation_notify_rest_updated
+    AFTER UPDATE ON public.reservation
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION public.notify_rest_changed();
@@ -0,0 +1,15 @@
+-- Events are written in the transaction of the change they describe and
+-- published by the relay in id order. Writers of one key are serialized by an
+-- advisory lock, so the ids of a key are committed in order. Published events
+-- are kept with the time they were published.
+CREATE TABLE public.outbox (
+    id           bigserial   PRIMARY KEY,
+    event_id     uuid        NOT NULL UNIQUE,
+    topic        text        NOT NULL,
+    key          text        NOT NULL,
+    payload      jsonb       NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    published_at timestamptz
+);
+
+CREATE INDEX outbox_pending_idx ON public.outbox (id) WHERE published_at IS NULL;
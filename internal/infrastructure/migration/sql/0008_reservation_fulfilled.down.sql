@@ -0,0 +1,4 @@
+-- The shipped stock is gone, so fulfilled reservations must not hold again.
+UPDATE public.reservation SET status = 2 WHERE status = 4;
+ALTER TABLE public.reservation DROP CONSTRAINT reservation_status_check;
+ALTER TABLE public.reservation ADD CONSTRAINT reservation_status_check CHECK (status BETWEEN 0 AND 3);
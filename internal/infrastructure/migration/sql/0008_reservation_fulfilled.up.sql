@@ -0,0 +1,4 @@
+-- status 4: fulfilled, a confirmed reservation whose stock was shipped to the
+-- order. Fulfilled reservations no longer hold stock.
+ALTER TABLE public.reservation DROP CONSTRAINT reservation_status_check;
+ALTER TABLE public.reservation ADD CONSTRAINT reservation_status_check CHECK (status BETWEEN 0 AND 4);
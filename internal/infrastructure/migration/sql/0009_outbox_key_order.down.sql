@@ -0,0 +1 @@
+DROP INDEX public.outbox_pending_key_idx;
@@ -0,0 +1,2 @@
+-- Lets the relay find an earlier unpublished event of the same key.
+CREATE INDEX outbox_pending_key_idx ON public.outbox (key, id) WHERE published_at IS NULL;
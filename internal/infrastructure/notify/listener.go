@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Handler receives the payload of a notification.
+type Handler func(ctx context.Context, payload string)
+
+// Listener listens to Postgres notification channels on one dedicated
+// connection and dispatches payloads to the handlers of the channel. A lost
+// connection is re-established after the retry delay and every channel is
+// subscribed again.
+type Listener struct {
+	pool       *pgxpool.Pool
+	retryDelay time.Duration
+
+	mu         sync.RWMutex
+	handlers   map[string][]Handler
+	subscribed []func()
+}
+
+func NewListener(pool *pgxpool.Pool, retryDelay time.Duration) *Listener {
+	return &Listener{pool: pool, retryDelay: retryDelay, handlers: make(map[string][]Handler)}
+}
+
+// Handle registers fn for the channel. Handlers must be registered before Run.
+func (l *Listener) Handle(channel string, fn Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[channel] = append(l.handlers[channel], fn)
+}
+
+// OnSubscribe registers fn to run every time the channels are (re)subscribed.
+// Notifications sent while the listener was disconnected are lost, so this
+// is the place to resynchronize.
+func (l *Listener) OnSubscribe(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribed = append(l.subscribed, fn)
+}
+
+// Run listens until ctx is done.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("notification listener disconnected", "error", err, "retry_in", l.retryDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection keeps listening, so it must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+	l.mu.RLock()
+	channels := make([]string, 0, len(l.handlers))
+	for channel := range l.handlers {
+		channels = append(channels, channel)
+	}
+	subscribed := l.subscribed
+	l.mu.RUnlock()
+	for _, channel := range channels {
+		if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+	}
+	for _, fn := range subscribed {
+		fn()
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.dispatch(ctx, notification.Channel, notification.Payload)
+	}
+}
+
+func (l *Listener) dispatch(ctx context.Context, channel, payload string) {
+	l.mu.RLock()
+	handlers := l.handlers[channel]
+	l.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, payload)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func TestRestFeedReceivesCommittedChanges(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	listener := NewListener(pool, 10*time.Millisecond)
+	changes := make(chan *domain.RestChange, 3)
+	NewRestFeed(listener).Subscribe(func(_ context.Context, change *domain.RestChange) {
+		changes <- change
+	})
+	subscribed := make(chan struct{}, 1)
+	listener.OnSubscribe(func() {
+		subscribed <- struct{}{}
+	})
+	go listener.Run(ctx)
+	<-subscribed
+
+	pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(5))
+	_, err := pool.Exec(ctx, `UPDATE public.rest SET quantity = 2 WHERE warehouse_id = $1`, warehouseID)
+	require.NoError(t, err)
+	// Neither a reservation nor a rest update keeping the quantity reaches the feed.
+	_, err = pool.Exec(ctx, `INSERT INTO public.reservation (id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, 1, 0, now() + interval '1 hour')`, *guid.New(), *guid.New(), filialID, warehouseID, productID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE public.rest SET integration_id = $2 WHERE warehouse_id = $1`, warehouseID, *guid.New())
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE public.rest SET quantity = 4 WHERE warehouse_id = $1`, warehouseID)
+	require.NoError(t, err)
+
+	inserted := <-changes
+	assert.Equal(t, domain.RestChangeOpINSERT, inserted.Op)
+	assert.Equal(t, "5", inserted.New.Quantity.String())
+	updated := <-changes
+	assert.Equal(t, domain.RestChangeOpUPDATE, updated.Op)
+	assert.Equal(t, "-3", updated.Delta().String())
+	assert.Equal(t, productID, updated.New.ProductID)
+	refilled := <-changes
+	assert.Equal(t, "2", refilled.Delta().String())
+}
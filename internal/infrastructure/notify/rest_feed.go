@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+// RestChangedChannel is notified by the rest and reservation triggers. Rest
+// caches invalidate by the key of every notification, the feed decodes only
+// the rest changes.
+const RestChangedChannel = "rest_changed"
+
+var restChangeOps = map[string]domain.RestChangeOp{
+	"INSERT": domain.RestChangeOpINSERT,
+	"UPDATE": domain.RestChangeOpUPDATE,
+	"DELETE": domain.RestChangeOpDELETE,
+}
+
+// restChangePayload carries the rest key of the change. The quantities are
+// set for rest changes only: the old one unless inserted, the new one unless
+// deleted.
+type restChangePayload struct {
+	Table       string           `json:"table"`
+	Op          string           `json:"op"`
+	FilialID    *string          `json:"filial_id"`
+	WarehouseID string           `json:"warehouse_id"`
+	ProductID   string           `json:"product_id"`
+	OldQuantity *decimal.Decimal `json:"old_quantity"`
+	NewQuantity *decimal.Decimal `json:"new_quantity"`
+}
+
+// RestFeed decodes rest change notifications and fans them out to the
+// subscribed handlers in the order they were committed.
+type RestFeed struct {
+	mu       sync.RWMutex
+	handlers []domain.RestChangeHandler
+}
+
+// NewRestFeed creates a feed fed by the listener.
+func NewRestFeed(listener *Listener) *RestFeed {
+	feed := &RestFeed{}
+	listener.Handle(RestChangedChannel, feed.handle)
+	return feed
+}
+
+func (f *RestFeed) Subscribe(handler domain.RestChangeHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers = append(f.handlers, handler)
+}
+
+func (f *RestFeed) handle(ctx context.Context, payload string) {
+	change, err := decodeRestChange(payload)
+	if err != nil {
+		slog.Warn("malformed rest change notification", "payload", payload, "error", err)
+		return
+	}
+	if change == nil {
+		return
+	}
+	f.mu.RLock()
+	handlers := f.handlers
+	f.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, change)
+	}
+}
+
+// decodeRestChange returns nil for notifications that do not change a rest
+// quantity, such as reservation changes.
+func decodeRestChange(payload string) (*domain.RestChange, error) {
+	var p restChangePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, err
+	}
+	if p.Table != "rest" {
+		return nil, nil
+	}
+	op, ok := restChangeOps[p.Op]
+	if !ok {
+		return nil, fmt.Errorf("unknown op %q", p.Op)
+	}
+	if p.OldQuantity == nil && p.NewQuantity == nil {
+		return nil, fmt.Errorf("%s without quantities", p.Op)
+	}
+	if p.OldQuantity != nil && p.NewQuantity != nil && p.OldQuantity.Equal(*p.NewQuantity) {
+		return nil, nil
+	}
+	key, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	change := &domain.RestChange{Op: op}
+	if p.OldQuantity != nil {
+		old := *key
+		old.Quantity = *p.OldQuantity
+		change.Old = &old
+	}
+	if p.NewQuantity != nil {
+		changed := *key
+		changed.Quantity = *p.NewQuantity
+		change.New = &changed
+	}
+	return change, nil
+}
+
+// key returns a rest holding the key of the change.
+func (p *restChangePayload) key() (*domain.Rest, error) {
+	warehouseID, err := parseGuid("warehouse_id", p.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := parseGuid("product_id", p.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	rest := &domain.Rest{WarehouseID: warehouseID, ProductID: productID}
+	if rest.FilialID, err = parseOptionalGuid("filial_id", p.FilialID); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+func parseGuid(field, s string) (guid.Guid, error) {
+	g, err := guid.ParseString(s)
+	if err != nil {
+		return guid.Guid{}, fmt.Errorf("%s: %w", field, err)
+	}
+	return *g, nil
+}
+
+func parseOptionalGuid(field string, s *string) (*guid.Guid, error) {
+	if s == nil {
+		return nil, nil
+	}
+	g, err := parseGuid(field, *s)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
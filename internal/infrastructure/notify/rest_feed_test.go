@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestFeedFanOut(t *testing.T) {
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	notification := func(table, op, quantities string) string {
+		return `{"table":"` + table + `","op":"` + op + `","filial_id":"` + filialID.String() + `",` +
+			`"warehouse_id":"` + warehouseID.String() + `","product_id":"` + productID.String() + `",` + quantities + `}`
+	}
+	listener := NewListener(nil, 0)
+	feed := NewRestFeed(listener)
+	var first, second []*domain.RestChange
+	feed.Subscribe(func(_ context.Context, change *domain.RestChange) {
+		first = append(first, change)
+	})
+	feed.Subscribe(func(_ context.Context, change *domain.RestChange) {
+		second = append(second, change)
+	})
+
+	listener.dispatch(ctx, RestChangedChannel, notification("rest", "UPDATE", `"old_quantity":10.5,"new_quantity":7`))
+	listener.dispatch(ctx, RestChangedChannel, notification("rest", "UPDATE", `"old_quantity":7,"new_quantity":7.0`))
+	listener.dispatch(ctx, RestChangedChannel, notification("reservation", "INSERT", `"old_quantity":null,"new_quantity":null`))
+	listener.dispatch(ctx, RestChangedChannel, notification("rest", "DELETE", `"old_quantity":7,"new_quantity":null`))
+	listener.dispatch(ctx, RestChangedChannel, notification("rest", "TRUNCATE", `"old_quantity":null,"new_quantity":null`))
+	listener.dispatch(ctx, RestChangedChannel, notification("rest", "INSERT", `"old_quantity":null,"new_quantity":null`))
+	listener.dispatch(ctx, "other", notification("rest", "INSERT", `"old_quantity":null,"new_quantity":1`))
+
+	require.Len(t, first, 2)
+	assert.Equal(t, first, second)
+	update := first[0]
+	assert.Equal(t, domain.RestChangeOpUPDATE, update.Op)
+	assert.Equal(t, "10.5", update.Old.Quantity.String())
+	assert.Equal(t, "7", update.New.Quantity.String())
+	assert.Equal(t, "-3.5", update.Delta().String())
+	assert.Equal(t, &filialID, update.New.FilialID)
+	assert.Equal(t, warehouseID, update.New.WarehouseID)
+	assert.Equal(t, productID, update.New.ProductID)
+	assert.Equal(t, update.Old.ProductID, update.New.ProductID)
+	deleted := first[1]
+	assert.Nil(t, deleted.New)
+	assert.Same(t, deleted.Old, deleted.Rest())
+	assert.Equal(t, "-7", deleted.Delta().String())
+}
@@ -1,5 +1,62 @@
 package persistance
 
-import "errors"
+import (
+	"errors"
 
-var ErrRestNotFound = errors.New("rest not found")
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	lockNotAvailableCode = "55P03"
+	checkViolationCode   = "23514"
+	uniqueViolationCode  = "23505"
+
+	restQuantityCheck = "rest_quantity_non_negative"
+)
+
+var ErrRestNotFound = domain.ErrRestNotFound
+
+// LockNotAvailableError is returned when a NOWAIT locking read hits a row
+// locked by another transaction. It matches domain.ErrLockNotAvailable.
+type LockNotAvailableError struct {
+	Err *pgconn.PgError
+}
+
+func (e *LockNotAvailableError) Error() string {
+	return domain.ErrLockNotAvailable.Error() + ": " + e.Err.Message
+}
+
+func (e *LockNotAvailableError) Is(target error) bool {
+	return target == domain.ErrLockNotAvailable
+}
+
+func (e *LockNotAvailableError) Unwrap() error {
+	return e.Err
+}
+
+func wrapLockError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == lockNotAvailableCode {
+		return &LockNotAvailableError{Err: pgErr}
+	}
+	return err
+}
+
+// wrapCheckError maps the non-negative rest constraint to domain.ErrNegativeRest.
+func wrapCheckError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == checkViolationCode && pgErr.ConstraintName == restQuantityCheck {
+		return domain.ErrNegativeRest
+	}
+	return err
+}
+
+// wrapUniqueError maps a violation of the unique constraint to target.
+func wrapUniqueError(err error, constraint string, target error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraint {
+		return target
+	}
+	return err
+}
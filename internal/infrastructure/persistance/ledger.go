@@ -0,0 +1,61 @@
+package persistance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/db"
+
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	appendMovementQuery = `INSERT INTO public.stock_movement
+				(id, filial_id, warehouse_id, product_id, integration_id, delta, reason, document_id, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	// restAsOfQuery returns a zero rest when no movement precedes the moment.
+	restAsOfQuery = `SELECT r.id, COALESCE(SUM(m.delta), 0), r.filial_id, r.integration_id, r.warehouse_id, r.product_id
+				FROM public.rest r
+				LEFT JOIN public.stock_movement m ON m.filial_id IS NOT DISTINCT FROM r.filial_id
+				AND m.warehouse_id = r.warehouse_id AND m.product_id = r.product_id AND m.created_at <= $4
+				WHERE r.filial_id IS NOT DISTINCT FROM $1 AND r.warehouse_id = $2 AND r.product_id = $3
+				GROUP BY r.id`
+)
+
+type LedgerRepository struct {
+	db db.QueryExecutor
+}
+
+func NewLedgerRepository(db db.QueryExecutor) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+func (r *LedgerRepository) Append(ctx context.Context, movement *domain.Movement) error {
+	_, err := r.db.Exec(ctx, appendMovementQuery,
+		movement.MovementID,
+		movement.FilialID,
+		movement.WarehouseID,
+		movement.ProductID,
+		movement.IntegrationID,
+		movement.Delta,
+		int16(movement.Reason),
+		movement.DocumentID,
+		movement.CreatedAt)
+	return err
+}
+
+func (r *LedgerRepository) RestAsOf(ctx context.Context, filialID *guid.Guid, warehouseID guid.Guid, productID guid.Guid, at time.Time) (*domain.Rest, error) {
+	var rest domain.Rest
+	err := r.db.QueryRow(ctx, restAsOfQuery, filialID, warehouseID, productID, at).
+		Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRestNotFound
+		}
+		return nil, err
+	}
+	return &rest, nil
+}
@@ -0,0 +1,52 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedMovement(t *testing.T, pool *pgxpool.Pool, filialID *guid.Guid, warehouseID, productID guid.Guid, delta int64, at time.Time) {
+	t.Helper()
+	_, err := pool.Exec(context.Background(), `INSERT INTO public.stock_movement
+		(id, filial_id, warehouse_id, product_id, delta, reason, document_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7)`, *guid.New(), filialID, warehouseID, productID, decimal.NewFromInt(delta), *guid.New(), at)
+	require.NoError(t, err)
+}
+
+func TestLedgerRestAsOf(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	restID := pgtest.SeedRest(t, pool, nil, warehouseID, productID, decimal.NewFromInt(5))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedMovement(t, pool, nil, warehouseID, productID, 8, start)
+	seedMovement(t, pool, nil, warehouseID, productID, -3, start.Add(time.Hour))
+	sut := NewLedgerRepository(pool)
+
+	rest, err := sut.RestAsOf(ctx, nil, warehouseID, productID, start.Add(30*time.Minute))
+
+	require.NoError(t, err)
+	assert.Equal(t, restID, rest.RestID)
+	assert.Nil(t, rest.FilialID)
+	assert.Equal(t, "8", rest.Quantity.String())
+
+	rest, err = sut.RestAsOf(ctx, nil, warehouseID, productID, start.Add(-time.Minute))
+
+	require.NoError(t, err)
+	assert.True(t, rest.Quantity.IsZero())
+
+	_, err = sut.RestAsOf(ctx, nil, warehouseID, *guid.New(), start)
+
+	assert.ErrorIs(t, err, ErrRestNotFound)
+}
@@ -0,0 +1,81 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/db"
+)
+
+const (
+	// outboxKeyLockClass is the first key of the advisory locks serializing
+	// the writers of one event key. Ids are taken at insert time, so without
+	// it a transaction could commit a lower id of the key after a higher one
+	// was already published. Writers of different keys do not wait.
+	outboxKeyLockClass  = 2
+	lockOutboxKeyQuery  = `SELECT pg_advisory_xact_lock($1, hashtext($2))`
+	addOutboxEventQuery = `INSERT INTO public.outbox (event_id, topic, key, payload, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id`
+	// pendingOutboxQuery skips the events locked by another relay and holds
+	// back the events of a key that still has an earlier event outside the
+	// batch, so relays share the work without reordering a key.
+	pendingOutboxQuery = `WITH batch AS (
+					SELECT id FROM public.outbox
+					WHERE published_at IS NULL
+					ORDER BY id
+					LIMIT $1
+					FOR UPDATE SKIP LOCKED
+				)
+				SELECT o.id, o.event_id, o.topic, o.key, o.payload, o.created_at FROM public.outbox o
+				JOIN batch b ON b.id = o.id
+				WHERE NOT EXISTS (
+					SELECT 1 FROM public.outbox e
+					WHERE e.key = o.key AND e.published_at IS NULL AND e.id < o.id
+					AND e.id NOT IN (SELECT id FROM batch)
+				)
+				ORDER BY o.id`
+	markOutboxPublishedQuery = `UPDATE public.outbox SET published_at = $2 WHERE id = ANY($1)`
+)
+
+type OutboxRepository struct {
+	db db.QueryExecutor
+}
+
+func NewOutboxRepository(db db.QueryExecutor) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Add holds the lock of the event key until the transaction ends, so the
+// events of a key are committed in id order. Events should be added last to
+// keep the lock short.
+func (r *OutboxRepository) Add(ctx context.Context, event *domain.OutboxEvent) error {
+	if _, err := r.db.Exec(ctx, lockOutboxKeyQuery, int32(outboxKeyLockClass), event.Key); err != nil {
+		return err
+	}
+	return r.db.QueryRow(ctx, addOutboxEventQuery, event.EventID, event.Topic, event.Key, event.Payload, event.CreatedAt).
+		Scan(&event.Position)
+}
+
+func (r *OutboxRepository) Pending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	rows, err := r.db.Query(ctx, pendingOutboxQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]*domain.OutboxEvent, 0)
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err = rows.Scan(&event.Position, &event.EventID, &event.Topic, &event.Key, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, positions []int64, at time.Time) error {
+	_, err := r.db.Exec(ctx, markOutboxPublishedQuery, positions, at)
+	return err
+}
@@ -0,0 +1,142 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxPendingInOrder(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	sut := NewUnitOfWork(pool)
+	added := make([]*domain.OutboxEvent, 0, 3)
+	for _, key := range []string{"a", "b", "c"} {
+		event, err := domain.NewOutboxEvent("test", key, map[string]string{"key": key}, now)
+		require.NoError(t, err)
+		require.NoError(t, sut.Outbox().Add(ctx, event))
+		added = append(added, event)
+	}
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		pending, err := work.Outbox().Pending(ctx, 2)
+		if err != nil {
+			return err
+		}
+		require.Len(t, pending, 2)
+		assert.Equal(t, added[0].Position, pending[0].Position)
+		assert.Equal(t, added[0].EventID, pending[0].EventID)
+		assert.JSONEq(t, `{"key":"a"}`, string(pending[0].Payload))
+		assert.Equal(t, "b", pending[1].Key)
+		return work.Outbox().MarkPublished(ctx, []int64{pending[0].Position, pending[1].Position}, now)
+	})
+	require.NoError(t, err)
+
+	pending, err := sut.Outbox().Pending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, added[2].Position, pending[0].Position)
+}
+
+func TestOutboxRolledBackWithTheChange(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	sut := NewUnitOfWork(pool)
+	abort := errors.New("abort")
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		event, err := domain.NewOutboxEvent("test", "key", nil, time.Now())
+		if err != nil {
+			return err
+		}
+		if err = work.Outbox().Add(ctx, event); err != nil {
+			return err
+		}
+		return abort
+	})
+
+	require.ErrorIs(t, err, abort)
+	pending, err := sut.Outbox().Pending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestOutboxInterleavedWritersCommitInOrder(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	first, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer first.Rollback(ctx)
+	second, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer second.Rollback(ctx)
+	a1, err := domain.NewOutboxEvent("test", "a", nil, time.Now())
+	require.NoError(t, err)
+	a2, err := domain.NewOutboxEvent("test", "a", nil, time.Now())
+	require.NoError(t, err)
+	b, err := domain.NewOutboxEvent("test", "b", nil, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, NewOutboxRepository(first).Add(ctx, a1))
+	require.NoError(t, NewOutboxRepository(pool).Add(ctx, b), "writers of other keys are not blocked")
+
+	added := make(chan error, 1)
+	go func() {
+		if err := NewOutboxRepository(second).Add(ctx, a2); err != nil {
+			added <- err
+			return
+		}
+		added <- second.Commit(ctx)
+	}()
+	select {
+	case err = <-added:
+		t.Fatalf("second writer of the key was not blocked by the first one: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.NoError(t, first.Commit(ctx))
+	require.NoError(t, <-added)
+
+	pending, err := NewOutboxRepository(pool).Pending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, a1.EventID, pending[0].EventID)
+	assert.Equal(t, b.EventID, pending[1].EventID)
+	assert.Equal(t, a2.EventID, pending[2].EventID)
+	assert.Less(t, a1.Position, a2.Position)
+}
+
+func TestOutboxConcurrentRelaysKeepKeyOrder(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	added := make([]*domain.OutboxEvent, 0, 3)
+	for _, key := range []string{"a", "a", "b"} {
+		event, err := domain.NewOutboxEvent("test", key, nil, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, NewOutboxRepository(pool).Add(ctx, event))
+		added = append(added, event)
+	}
+	first, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer first.Rollback(ctx)
+	second, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer second.Rollback(ctx)
+
+	firstBatch, err := NewOutboxRepository(first).Pending(ctx, 1)
+	require.NoError(t, err)
+	secondBatch, err := NewOutboxRepository(second).Pending(ctx, 10)
+	require.NoError(t, err)
+
+	require.Len(t, firstBatch, 1)
+	assert.Equal(t, added[0].EventID, firstBatch[0].EventID)
+	require.Len(t, secondBatch, 1, "the second event of a waits for the first one")
+	assert.Equal(t, added[2].EventID, secondBatch[0].EventID)
+}
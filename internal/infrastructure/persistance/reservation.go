@@ -0,0 +1,170 @@
+package persistance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/db"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// heldReservation matches reservations aliased as s that still hold stock:
+	// confirmed ones and active ones that have not expired yet.
+	heldReservation = `(s.status = 1 OR (s.status = 0 AND s.expires_at > now()))`
+	// orderLockClass is the first key of the order advisory locks. The other
+	// classes, such as the outbox key one, lock other aggregates.
+	orderLockClass = 1
+
+	lockOrderQuery = `SELECT pg_advisory_xact_lock($1, hashtext($2::uuid::text))`
+
+	restQuantityQuery = `SELECT r.quantity FROM public.rest r
+				WHERE r.filial_id = $1 AND r.warehouse_id = $2 AND r.product_id = $3`
+	heldQuantityQuery = `SELECT COALESCE(SUM(s.quantity), 0) FROM public.reservation s
+				WHERE s.filial_id = $1 AND s.warehouse_id = $2 AND s.product_id = $3 AND ` + heldReservation
+	insertReservationQuery = `INSERT INTO public.reservation
+				(id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	reservationsByOrderQuery = `SELECT id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at, created_at
+				FROM public.reservation WHERE order_id = $1 ORDER BY created_at, id`
+	setReservationStatusQuery = `UPDATE public.reservation SET status = $3
+				WHERE order_id = $1 AND status = $2 AND (status <> 0 OR expires_at > now())`
+	fulfilReservationsQuery = `UPDATE public.reservation SET status = 4
+				WHERE order_id = $1 AND warehouse_id = $2 AND status = 1
+				RETURNING id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at, created_at`
+	expireReservationsQuery = `WITH stale AS (
+					SELECT id FROM public.reservation
+					WHERE status = 0 AND expires_at <= now()
+					ORDER BY expires_at
+					LIMIT $1
+					FOR UPDATE SKIP LOCKED
+				)
+				UPDATE public.reservation r SET status = 3 FROM stale WHERE r.id = stale.id
+				RETURNING r.id, r.order_id, r.filial_id, r.warehouse_id, r.product_id, r.quantity, r.status, r.expires_at, r.created_at`
+)
+
+type ReservationRepository struct {
+	db db.QueryExecutor
+}
+
+func NewReservationRepository(db db.QueryExecutor) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// Hold relies on the caller's lock on the rest row: concurrent holds of the
+// same rest are serialized and each of them sees the reservations committed
+// before it.
+func (r *ReservationRepository) Hold(ctx context.Context, reservation *domain.Reservation) error {
+	var quantity decimal.Decimal
+	err := r.db.QueryRow(ctx, restQuantityQuery, reservation.FilialID, reservation.WarehouseID, reservation.ProductID).Scan(&quantity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrInsufficientStock
+		}
+		return err
+	}
+	var held decimal.Decimal
+	err = r.db.QueryRow(ctx, heldQuantityQuery, reservation.FilialID, reservation.WarehouseID, reservation.ProductID).Scan(&held)
+	if err != nil {
+		return err
+	}
+	if quantity.Sub(held).LessThan(reservation.Quantity) {
+		return domain.ErrInsufficientStock
+	}
+	_, err = r.db.Exec(ctx, insertReservationQuery,
+		reservation.ReservationID,
+		reservation.OrderID,
+		reservation.FilialID,
+		reservation.WarehouseID,
+		reservation.ProductID,
+		reservation.Quantity,
+		int16(reservation.Status),
+		reservation.ExpiresAt,
+		reservation.CreatedAt)
+	return err
+}
+
+func (r *ReservationRepository) LockOrder(ctx context.Context, orderID guid.Guid) error {
+	_, err := r.db.Exec(ctx, lockOrderQuery, int32(orderLockClass), orderID)
+	return err
+}
+
+func (r *ReservationRepository) GetByOrder(ctx context.Context, orderID guid.Guid) ([]*domain.Reservation, error) {
+	rows, err := r.db.Query(ctx, reservationsByOrderQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reservations := make([]*domain.Reservation, 0)
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, rows.Err()
+}
+
+// SetStatus never moves active reservations that are already past their expiry.
+func (r *ReservationRepository) SetStatus(ctx context.Context, orderID guid.Guid, from domain.ReservationStatus, to domain.ReservationStatus) (int64, error) {
+	tag, err := r.db.Exec(ctx, setReservationStatusQuery, orderID, int16(from), int16(to))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *ReservationRepository) Fulfil(ctx context.Context, orderID guid.Guid, warehouseID guid.Guid) ([]*domain.Reservation, error) {
+	rows, err := r.db.Query(ctx, fulfilReservationsQuery, orderID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	return collectReservations(rows, 0)
+}
+
+func (r *ReservationRepository) ExpireBatch(ctx context.Context, limit int) ([]*domain.Reservation, error) {
+	rows, err := r.db.Query(ctx, expireReservationsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectReservations(rows, limit)
+}
+
+func collectReservations(rows pgx.Rows, capacity int) ([]*domain.Reservation, error) {
+	defer rows.Close()
+	reservations := make([]*domain.Reservation, 0, capacity)
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, rows.Err()
+}
+
+func scanReservation(row pgx.Row) (*domain.Reservation, error) {
+	var (
+		reservation domain.Reservation
+		status      int16
+	)
+	err := row.Scan(
+		&reservation.ReservationID,
+		&reservation.OrderID,
+		&reservation.FilialID,
+		&reservation.WarehouseID,
+		&reservation.ProductID,
+		&reservation.Quantity,
+		&status,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	reservation.Status = domain.ReservationStatus(status)
+	return &reservation, nil
+}
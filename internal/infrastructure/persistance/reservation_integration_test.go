@@ -0,0 +1,66 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationRepositoryLockOrder(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	orderID := *guid.New()
+	first, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer first.Rollback(ctx)
+	second, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer second.Rollback(ctx)
+	require.NoError(t, NewReservationRepository(first).LockOrder(ctx, orderID))
+	require.NoError(t, NewReservationRepository(second).LockOrder(ctx, *guid.New()), "other orders are not blocked")
+
+	locked := make(chan error, 1)
+	go func() {
+		locked <- NewReservationRepository(second).LockOrder(ctx, orderID)
+	}()
+	select {
+	case err = <-locked:
+		t.Fatalf("second reservation of the order was not blocked: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.NoError(t, first.Commit(ctx))
+	require.NoError(t, <-locked)
+}
+
+func TestReservationRepositoryFulfilReleasesShippedStock(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	orderID := *guid.New()
+	pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(5))
+	_, err := pool.Exec(ctx, `INSERT INTO public.reservation (id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, 3, 1, now() + interval '1 hour')`, *guid.New(), orderID, filialID, warehouseID, productID)
+	require.NoError(t, err)
+	rests := NewRestRepository(pool)
+	_, err = rests.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(-3))
+	require.ErrorIs(t, err, domain.ErrInsufficientStock, "the confirmed reservation holds the stock")
+
+	fulfilled, err := NewReservationRepository(pool).Fulfil(ctx, orderID, warehouseID)
+	require.NoError(t, err)
+	require.Len(t, fulfilled, 1)
+	assert.Equal(t, domain.ReservationStatusFULFILLED, fulfilled[0].Status)
+	rest, err := rests.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(-3))
+	require.NoError(t, err)
+	assert.Equal(t, "2", rest.Quantity.String())
+}
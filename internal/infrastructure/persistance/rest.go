@@ -3,32 +3,242 @@ package persistance
 import (
 	"context"
 	"errors"
+
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/DimKa163/stocks/internal/shared/db"
 
 	"github.com/beevik/guid"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 const (
-	restQuery = `SELECT id, quantity, filial_id, integration_ID, warehouse_id, product_id FROM public.rest
-				WHERE filial_id = $1 AND warehouse_id = $2 AND product_id = $3`
+	restColumns = `r.id, r.quantity, (SELECT COALESCE(SUM(s.quantity), 0) FROM public.reservation s
+				WHERE s.filial_id = r.filial_id AND s.warehouse_id = r.warehouse_id AND s.product_id = r.product_id
+				AND ` + heldReservation + `), r.filial_id, r.integration_id, r.warehouse_id, r.product_id`
+	restQuery = `SELECT ` + restColumns + ` FROM public.rest r
+				WHERE r.filial_id = $1 AND r.warehouse_id = $2 AND r.product_id = $3`
+	restManyQuery = `SELECT ` + restColumns + ` FROM public.rest r
+				WHERE r.filial_id = $1 AND r.warehouse_id = ANY($2) AND r.product_id = ANY($3)
+				ORDER BY r.warehouse_id, r.product_id`
+	restPairsQuery = `SELECT ` + restColumns + ` FROM public.rest r
+				WHERE r.filial_id = $1
+				AND (r.warehouse_id, r.product_id) IN (SELECT * FROM unnest($2::uuid[], $3::uuid[]))
+				ORDER BY r.warehouse_id, r.product_id`
+	restScanQuery = `SELECT r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id FROM public.rest r
+				WHERE ($1::uuid IS NULL OR r.filial_id = $1)
+				AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR r.warehouse_id = ANY($2))
+				AND (COALESCE(cardinality($3::uuid[]), 0) = 0 OR r.product_id = ANY($3))
+				AND (NOT $4 OR r.quantity <> 0)
+				ORDER BY r.filial_id, r.warehouse_id, r.product_id`
+	adjustRestQuery = `INSERT INTO public.rest AS r (id, filial_id, warehouse_id, product_id, quantity)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (filial_id, warehouse_id, product_id) DO UPDATE SET quantity = r.quantity + EXCLUDED.quantity
+				RETURNING r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id`
+	// syncMatchQuery finds the rest of a snapshot rest by integration id and
+	// by its filial, warehouse and product.
+	syncMatchQuery = `SELECT r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id FROM public.rest r
+				WHERE r.integration_id = $1
+				OR (r.filial_id IS NOT DISTINCT FROM $2 AND r.warehouse_id = $3 AND r.product_id = $4)
+				FOR UPDATE`
+	insertSyncRestQuery = `INSERT INTO public.rest AS r (id, integration_id, filial_id, warehouse_id, product_id, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id`
+	updateSyncRestQuery = `UPDATE public.rest r SET integration_id = $2, quantity = $3 WHERE r.id = $1
+				RETURNING r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id`
+	zeroMissingRestsQuery = `WITH old AS (
+					SELECT id, quantity FROM public.rest
+					WHERE integration_id IS NOT NULL AND integration_id <> ALL($2) AND quantity <> 0
+					AND ($1::uuid IS NULL OR filial_id = $1)
+					FOR UPDATE
+				)
+				UPDATE public.rest r SET quantity = 0 FROM old WHERE r.id = old.id
+				RETURNING old.quantity, r.id, r.quantity, r.filial_id, r.integration_id, r.warehouse_id, r.product_id`
 )
 
 type RestRepository struct {
-	db db.QueryExecutor
+	db   db.QueryExecutor
+	lock domain.RowLock
 }
 
 func NewRestRepository(db db.QueryExecutor) *RestRepository {
 	return &RestRepository{db: db}
 }
 
-func (r *RestRepository) Get(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid) (*domain.Rest, error) {
+func (r *RestRepository) Each(ctx context.Context, filter domain.RestFilter, fn func(rest *domain.Rest) error) error {
+	rows, err := r.db.Query(ctx, restScanQuery, filter.FilialID, filter.WarehouseIDs, filter.ProductIDs, filter.NonZero)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rest domain.Rest
+		if err = rows.Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID); err != nil {
+			return err
+		}
+		if err = fn(&rest); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Adjust locks the rest before taking stock away, so a concurrent hold cannot
+// slip in between the check against the held reservations and the update.
+func (r *RestRepository) Adjust(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid, delta decimal.Decimal) (*domain.Rest, error) {
+	if delta.IsNegative() {
+		current, err := scanRest(r.db.QueryRow(ctx, restQuery+" FOR UPDATE OF r", filialID, warehouseID, productID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domain.ErrNegativeRest
+			}
+			return nil, err
+		}
+		quantity := current.Quantity.Add(delta)
+		if quantity.IsNegative() {
+			return nil, domain.ErrNegativeRest
+		}
+		if quantity.LessThan(current.Reserved) {
+			return nil, domain.ErrInsufficientStock
+		}
+	}
 	var rest domain.Rest
-	if err := r.db.QueryRow(ctx, restQuery, filialID, warehouseID, productID).Scan(&rest); err != nil {
+	err := r.db.QueryRow(ctx, adjustRestQuery, *guid.New(), filialID, warehouseID, productID, delta).
+		Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID)
+	if err != nil {
+		return nil, wrapCheckError(err)
+	}
+	if rest.Quantity.IsNegative() {
+		return nil, domain.ErrNegativeRest
+	}
+	return &rest, nil
+}
+
+// Sync matches the snapshot rest by integration id first. A rest created by
+// movements before the ERP knew it has no integration id yet, so it is matched
+// by filial, warehouse and product and the integration id is attached to it.
+// An integration id bound to a rest with another key, or a key bound to
+// another integration id, is rejected with ErrIntegrationKeyMismatch.
+func (r *RestRepository) Sync(ctx context.Context, snapshot *domain.SnapshotRest) (*domain.SyncResult, error) {
+	rows, err := r.db.Query(ctx, syncMatchQuery, snapshot.IntegrationID, snapshot.FilialID, snapshot.WarehouseID, snapshot.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Rest, error) {
+		var rest domain.Rest
+		err := row.Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID)
+		return &rest, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rest domain.Rest
+	switch {
+	case len(matches) == 0:
+		err = r.db.QueryRow(ctx, insertSyncRestQuery, *guid.New(), snapshot.IntegrationID, snapshot.FilialID,
+			snapshot.WarehouseID, snapshot.ProductID, snapshot.Quantity).
+			Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID)
+		if err != nil {
+			return nil, wrapCheckError(err)
+		}
+		return &domain.SyncResult{Outcome: domain.SyncOutcomeINSERTED, Rest: &rest}, nil
+	case len(matches) > 1 || !snapshot.Matches(matches[0]):
+		return nil, domain.ErrIntegrationKeyMismatch
+	}
+	current := matches[0]
+	if current.IntegrationID != nil && current.Quantity.Equal(snapshot.Quantity) {
+		return &domain.SyncResult{Outcome: domain.SyncOutcomeUNCHANGED}, nil
+	}
+	err = r.db.QueryRow(ctx, updateSyncRestQuery, current.RestID, snapshot.IntegrationID, snapshot.Quantity).
+		Scan(&rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID)
+	if err != nil {
+		return nil, wrapCheckError(err)
+	}
+	return &domain.SyncResult{Outcome: domain.SyncOutcomeUPDATED, Rest: &rest, Previous: current.Quantity}, nil
+}
+
+func (r *RestRepository) ZeroMissing(ctx context.Context, filialID *guid.Guid, keep []guid.Guid) ([]*domain.SyncResult, error) {
+	rows, err := r.db.Query(ctx, zeroMissingRestsQuery, filialID, keep)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results := make([]*domain.SyncResult, 0)
+	for rows.Next() {
+		var rest domain.Rest
+		result := domain.SyncResult{Outcome: domain.SyncOutcomeREMOVED, Rest: &rest}
+		if err = rows.Scan(&result.Previous, &rest.RestID, &rest.Quantity, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID); err != nil {
+			return nil, err
+		}
+		results = append(results, &result)
+	}
+	return results, rows.Err()
+}
+
+func (r *RestRepository) WithLock(lock domain.RowLock) domain.RestRepository {
+	return &RestRepository{db: r.db, lock: lock}
+}
+
+// lockClause locks only the rest rows, reservations are summed in a subquery.
+func (r *RestRepository) lockClause() string {
+	var clause string
+	switch r.lock.Strength {
+	case domain.LockNone:
+		return ""
+	case domain.LockForShare:
+		clause = " FOR SHARE OF r"
+	case domain.LockForUpdate:
+		clause = " FOR UPDATE OF r"
+	}
+	if r.lock.NoWait {
+		clause += " NOWAIT"
+	}
+	return clause
+}
+
+func (r *RestRepository) Get(ctx context.Context, filialID guid.Guid, warehouseID guid.Guid, productID guid.Guid) (*domain.Rest, error) {
+	rest, err := scanRest(r.db.QueryRow(ctx, restQuery+r.lockClause(), filialID, warehouseID, productID))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrRestNotFound
 		}
+		return nil, wrapLockError(err)
+	}
+	return rest, nil
+}
+
+func (r *RestRepository) GetMany(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	return r.many(ctx, restManyQuery, filialID, warehouseIDs, productIDs)
+}
+
+func (r *RestRepository) GetPairs(ctx context.Context, filialID guid.Guid, warehouseIDs []guid.Guid, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	return r.many(ctx, restPairsQuery, filialID, warehouseIDs, productIDs)
+}
+
+// many runs a query over restColumns with the repository lock, rows are
+// ordered so concurrent lockers take them in the same order.
+func (r *RestRepository) many(ctx context.Context, query string, args ...any) ([]*domain.Rest, error) {
+	rows, err := r.db.Query(ctx, query+r.lockClause(), args...)
+	if err != nil {
+		return nil, wrapLockError(err)
+	}
+	defer rows.Close()
+	rests := make([]*domain.Rest, 0)
+	for rows.Next() {
+		rest, err := scanRest(rows)
+		if err != nil {
+			return nil, wrapLockError(err)
+		}
+		rests = append(rests, rest)
+	}
+	return rests, wrapLockError(rows.Err())
+}
+
+// scanRest reads a row of restColumns. filial_id and integration_id are
+// nullable and stay nil for NULL.
+func scanRest(row pgx.Row) (*domain.Rest, error) {
+	var rest domain.Rest
+	if err := row.Scan(&rest.RestID, &rest.Quantity, &rest.Reserved, &rest.FilialID, &rest.IntegrationID, &rest.WarehouseID, &rest.ProductID); err != nil {
 		return nil, err
 	}
 	return &rest, nil
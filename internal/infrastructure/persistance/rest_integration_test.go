@@ -0,0 +1,158 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func TestRestRepositoryGet(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := guid.New()
+	integrationID := guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	restID := *guid.New()
+	_, err := pool.Exec(ctx, `INSERT INTO public.rest (id, filial_id, integration_id, warehouse_id, product_id, quantity)
+		VALUES ($1, $2, $3, $4, $5, $6)`, restID, filialID, integrationID, warehouseID, productID, decimal.RequireFromString("10.5"))
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO public.reservation (id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, now() + interval '1 hour')`, *guid.New(), *guid.New(), filialID, warehouseID, productID, decimal.NewFromInt(3))
+	require.NoError(t, err)
+	sut := NewRestRepository(pool)
+
+	rest, err := sut.Get(ctx, *filialID, warehouseID, productID)
+
+	require.NoError(t, err)
+	assert.Equal(t, restID, rest.RestID)
+	assert.Equal(t, filialID, rest.FilialID)
+	assert.Equal(t, integrationID, rest.IntegrationID)
+	assert.Equal(t, warehouseID, rest.WarehouseID)
+	assert.Equal(t, productID, rest.ProductID)
+	assert.Equal(t, "10.5", rest.Quantity.String())
+	assert.Equal(t, "3", rest.Reserved.String())
+	assert.Equal(t, "7.5", rest.Available().String())
+}
+
+func TestRestRepositoryGetNullIntegrationID(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(1))
+	sut := NewRestRepository(pool)
+
+	rest, err := sut.Get(ctx, filialID, warehouseID, productID)
+
+	require.NoError(t, err)
+	assert.Nil(t, rest.IntegrationID)
+	assert.True(t, rest.Reserved.IsZero())
+
+	_, err = sut.Get(ctx, filialID, warehouseID, *guid.New())
+	assert.ErrorIs(t, err, domain.ErrRestNotFound)
+}
+
+func TestRestRepositorySyncAttachesIntegrationID(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewRestRepository(pool)
+	created, err := sut.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4))
+	require.NoError(t, err)
+	snapshot := &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		FilialID:      &filialID,
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(7),
+	}
+
+	result, err := sut.Sync(ctx, snapshot)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncOutcomeUPDATED, result.Outcome)
+	assert.Equal(t, created.RestID, result.Rest.RestID)
+	assert.Equal(t, &snapshot.IntegrationID, result.Rest.IntegrationID)
+	assert.Equal(t, "3", result.Delta().String())
+
+	result, err = sut.Sync(ctx, snapshot)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncOutcomeUNCHANGED, result.Outcome)
+}
+
+func TestRestRepositorySyncKeyMismatch(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewRestRepository(pool)
+	snapshot := &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		FilialID:      &filialID,
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(1),
+	}
+	_, err := sut.Sync(ctx, snapshot)
+	require.NoError(t, err)
+
+	_, err = sut.Sync(ctx, &domain.SnapshotRest{
+		IntegrationID: snapshot.IntegrationID,
+		FilialID:      &filialID,
+		WarehouseID:   *guid.New(),
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(2),
+	})
+	assert.ErrorIs(t, err, domain.ErrIntegrationKeyMismatch)
+
+	_, err = sut.Sync(ctx, &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		FilialID:      &filialID,
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(2),
+	})
+	assert.ErrorIs(t, err, domain.ErrIntegrationKeyMismatch)
+}
+
+func TestRestRepositoryGetPairs(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseIDs := []guid.Guid{*guid.New(), *guid.New()}
+	productIDs := []guid.Guid{*guid.New(), *guid.New()}
+	for _, warehouseID := range warehouseIDs {
+		for _, productID := range productIDs {
+			pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(1))
+		}
+	}
+	sut := NewRestRepository(pool)
+
+	rests, err := sut.GetPairs(ctx, filialID, warehouseIDs, productIDs)
+
+	require.NoError(t, err)
+	require.Len(t, rests, 2)
+	pairs := map[guid.Guid]guid.Guid{}
+	for _, rest := range rests {
+		pairs[rest.WarehouseID] = rest.ProductID
+	}
+	assert.Equal(t, map[guid.Guid]guid.Guid{warehouseIDs[0]: productIDs[0], warehouseIDs[1]: productIDs[1]}, pairs)
+}
@@ -0,0 +1,75 @@
+package persistance
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+// RetryPolicy retries transactions that Postgres aborted because of
+// concurrent transactions. The zero value runs a transaction once.
+type RetryPolicy struct {
+	// MaxAttempts is the number of runs including the first one.
+	MaxAttempts int
+	// BaseDelay is doubled after every attempt up to MaxDelay, the actual
+	// delay is picked at random below it.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Observe, if set, is called once per transaction with the number of
+	// attempts made and the final error.
+	Observe func(attempts int, err error)
+}
+
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	attempts := 0
+	for {
+		attempts++
+		err := fn()
+		if err == nil || attempts >= p.MaxAttempts || !isRetryable(err) {
+			if p.Observe != nil {
+				p.Observe(attempts, err)
+			}
+			return err
+		}
+		timer := time.NewTimer(p.delay(attempts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = errors.Join(err, ctx.Err())
+			if p.Observe != nil {
+				p.Observe(attempts, err)
+			}
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns a random duration up to the capped exponential backoff of
+// the attempt that just failed.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff)
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+}
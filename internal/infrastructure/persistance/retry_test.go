@@ -0,0 +1,74 @@
+package persistance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	serialization := fmt.Errorf("commit: %w", &pgconn.PgError{Code: serializationFailureCode})
+	deadlock := &pgconn.PgError{Code: deadlockDetectedCode}
+	other := errors.New("other")
+	tests := []struct {
+		name     string
+		errs     []error
+		attempts int
+		err      error
+	}{
+		{name: "success is not retried", errs: []error{nil}, attempts: 1},
+		{name: "serialization failure is retried", errs: []error{serialization, deadlock, nil}, attempts: 3},
+		{name: "other errors are not retried", errs: []error{other}, attempts: 1, err: other},
+		{name: "attempts are limited", errs: []error{deadlock, deadlock, deadlock, nil}, attempts: 3, err: deadlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var observed int
+			policy := RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Microsecond,
+				MaxDelay:    time.Millisecond,
+				Observe: func(attempts int, err error) {
+					observed = attempts
+				},
+			}
+			calls := 0
+
+			err := policy.do(context.Background(), func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.attempts, calls)
+			assert.Equal(t, tt.attempts, observed)
+		})
+	}
+}
+
+func TestRetryPolicyZeroValueRunsOnce(t *testing.T) {
+	calls := 0
+
+	err := RetryPolicy{}.do(context.Background(), func() error {
+		calls++
+		return &pgconn.PgError{Code: serializationFailureCode}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 25 * time.Millisecond}
+
+	for i := 0; i < 100; i++ {
+		assert.Less(t, policy.delay(1), 10*time.Millisecond)
+		assert.Less(t, policy.delay(5), 25*time.Millisecond)
+		assert.Less(t, policy.delay(70), 25*time.Millisecond)
+	}
+}
@@ -0,0 +1,174 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/db"
+
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	stagingTable            = "rest_import"
+	stagingKey              = "rest_import_pkey"
+	createStagingTableQuery = `CREATE TEMP TABLE IF NOT EXISTS rest_import (
+					integration_id uuid PRIMARY KEY,
+					filial_id uuid,
+					warehouse_id uuid NOT NULL,
+					product_id uuid NOT NULL,
+					quantity numeric NOT NULL
+				) ON COMMIT DROP`
+	// stagingMismatchQuery finds staged rests that cannot be merged: their
+	// integration id belongs to a rest with another key, their key belongs to
+	// a rest with another integration id or to another staged rest.
+	stagingMismatchQuery = `SELECT EXISTS (
+					SELECT 1 FROM rest_import s JOIN public.rest r
+					ON (r.integration_id = s.integration_id
+						AND (r.filial_id IS DISTINCT FROM s.filial_id OR r.warehouse_id <> s.warehouse_id OR r.product_id <> s.product_id))
+					OR (r.integration_id <> s.integration_id
+						AND r.filial_id IS NOT DISTINCT FROM s.filial_id AND r.warehouse_id = s.warehouse_id AND r.product_id = s.product_id)
+				) OR EXISTS (
+					SELECT 1 FROM rest_import s
+					GROUP BY s.filial_id, s.warehouse_id, s.product_id HAVING count(*) > 1
+				)`
+	// stagingDiff matches a staged rest by integration id, or by key when the
+	// rest has no integration id yet, and keeps the ones that would change.
+	stagingDiff = `SELECT s.integration_id, s.filial_id, s.warehouse_id, s.product_id, r.id AS rest_id, r.quantity AS previous, s.quantity
+				FROM rest_import s
+				LEFT JOIN public.rest r ON r.integration_id = s.integration_id
+				OR (r.integration_id IS NULL
+					AND r.filial_id IS NOT DISTINCT FROM s.filial_id AND r.warehouse_id = s.warehouse_id AND r.product_id = s.product_id)
+				WHERE r.id IS NULL OR r.quantity <> s.quantity OR r.integration_id IS NULL`
+	// stagingLockQuery locks the rests the staged rests match, in one order so
+	// concurrent imports do not deadlock, before the diff reads them.
+	stagingLockQuery = `SELECT count(*) FROM (
+					SELECT r.id FROM public.rest r JOIN rest_import s ON r.integration_id = s.integration_id
+					OR (r.integration_id IS NULL
+						AND r.filial_id IS NOT DISTINCT FROM s.filial_id AND r.warehouse_id = s.warehouse_id AND r.product_id = s.product_id)
+					ORDER BY r.id FOR UPDATE OF r
+				) locked`
+	stagingDiffQuery = `SELECT integration_id, filial_id, warehouse_id, product_id, previous, quantity
+				FROM (` + stagingDiff + `) d
+				WHERE previous IS DISTINCT FROM quantity
+				ORDER BY warehouse_id, product_id, integration_id`
+	// stagingMergeQuery records the ledger from the merged rows, so every
+	// movement belongs to the rest that actually changed.
+	stagingMergeQuery = `WITH diff AS (` + stagingDiff + `),
+				updated AS (
+					UPDATE public.rest r SET integration_id = d.integration_id, quantity = d.quantity
+					FROM diff d WHERE r.id = d.rest_id
+					RETURNING r.filial_id, r.warehouse_id, r.product_id, r.integration_id, r.quantity, d.previous
+				),
+				inserted AS (
+					INSERT INTO public.rest AS r (id, integration_id, filial_id, warehouse_id, product_id, quantity)
+					SELECT gen_random_uuid(), integration_id, filial_id, warehouse_id, product_id, quantity
+					FROM diff WHERE rest_id IS NULL
+					RETURNING r.filial_id, r.warehouse_id, r.product_id, r.integration_id, r.quantity, NULL::numeric
+				),
+				merged AS (
+					SELECT * FROM updated UNION ALL SELECT * FROM inserted
+				)
+				INSERT INTO public.stock_movement
+				(id, filial_id, warehouse_id, product_id, integration_id, delta, reason, document_id, created_at)
+				SELECT gen_random_uuid(), m.filial_id, m.warehouse_id, m.product_id, m.integration_id,
+				m.quantity - COALESCE(m.previous, 0), $1, $2, $3
+				FROM merged m WHERE m.quantity <> COALESCE(m.previous, 0)`
+)
+
+var stagingColumns = []string{"integration_id", "filial_id", "warehouse_id", "product_id", "quantity"}
+
+type RestStagingRepository struct {
+	db db.QueryExecutor
+}
+
+func NewRestStagingRepository(db db.QueryExecutor) *RestStagingRepository {
+	return &RestStagingRepository{db: db}
+}
+
+// Stage copies the rests as they are read. The error of the source is
+// returned as is, the copy only reports that it failed.
+func (r *RestStagingRepository) Stage(ctx context.Context, next domain.SnapshotRestSource) (int64, error) {
+	if _, err := r.db.Exec(ctx, createStagingTableQuery); err != nil {
+		return 0, err
+	}
+	var readErr error
+	staged, err := r.db.CopyFrom(ctx, pgx.Identifier{stagingTable}, stagingColumns,
+		pgx.CopyFromFunc(func() ([]any, error) {
+			rest, err := next()
+			if err != nil {
+				readErr = err
+				return nil, err
+			}
+			if rest == nil {
+				return nil, nil
+			}
+			return []any{rest.IntegrationID, rest.FilialID, rest.WarehouseID, rest.ProductID, rest.Quantity}, nil
+		}))
+	if readErr != nil {
+		return 0, readErr
+	}
+	if err != nil {
+		return 0, wrapUniqueError(err, stagingKey, domain.ErrDuplicateIntegrationID)
+	}
+	return staged, nil
+}
+
+func (r *RestStagingRepository) Diff(ctx context.Context) ([]*domain.RestDiff, error) {
+	if err := r.lockRests(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.checkKeys(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, stagingDiffQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	diffs := make([]*domain.RestDiff, 0)
+	for rows.Next() {
+		var diff domain.RestDiff
+		if err = rows.Scan(&diff.IntegrationID, &diff.FilialID, &diff.WarehouseID, &diff.ProductID, &diff.Previous, &diff.Quantity); err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, &diff)
+	}
+	return diffs, rows.Err()
+}
+
+func (r *RestStagingRepository) Merge(ctx context.Context, documentID guid.Guid, at time.Time) (int64, error) {
+	if err := r.lockRests(ctx); err != nil {
+		return 0, err
+	}
+	if err := r.checkKeys(ctx); err != nil {
+		return 0, err
+	}
+	tag, err := r.db.Exec(ctx, stagingMergeQuery, int16(domain.MovementReasonSYNC), documentID, at)
+	if err != nil {
+		return 0, wrapCheckError(err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// lockRests locks the rests the staged rests match until the transaction
+// ends, so the diff and the merge see quantities no one else is changing.
+func (r *RestStagingRepository) lockRests(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, stagingLockQuery)
+	return err
+}
+
+// checkKeys returns ErrIntegrationKeyMismatch when a staged rest cannot be
+// merged without breaking one of the rest keys.
+func (r *RestStagingRepository) checkKeys(ctx context.Context) error {
+	var mismatch bool
+	if err := r.db.QueryRow(ctx, stagingMismatchQuery).Scan(&mismatch); err != nil {
+		return err
+	}
+	if mismatch {
+		return domain.ErrIntegrationKeyMismatch
+	}
+	return nil
+}
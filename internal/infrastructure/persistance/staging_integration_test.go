@@ -0,0 +1,109 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestStagingMergeAttachesIntegrationID(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	documentID := *guid.New()
+	sut := NewUnitOfWork(pool)
+	_, err := sut.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4))
+	require.NoError(t, err)
+	staged := &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		FilialID:      &filialID,
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(6),
+	}
+
+	var merged int64
+	err = sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		if _, err := work.Staging().Stage(ctx, domain.SnapshotRestsOf([]*domain.SnapshotRest{staged})); err != nil {
+			return err
+		}
+		merged, err = work.Staging().Merge(ctx, documentID, time.Now())
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), merged)
+	rest, err := sut.Rest().Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+	assert.Equal(t, &staged.IntegrationID, rest.IntegrationID)
+	assert.Equal(t, "6", rest.Quantity.String())
+	var delta decimal.Decimal
+	var movementWarehouseID guid.Guid
+	err = pool.QueryRow(ctx, `SELECT delta, warehouse_id FROM public.stock_movement WHERE document_id = $1`, documentID).
+		Scan(&delta, &movementWarehouseID)
+	require.NoError(t, err)
+	assert.Equal(t, "2", delta.String())
+	assert.Equal(t, warehouseID, movementWarehouseID)
+}
+
+func TestRestStagingMergeKeyMismatch(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	productID := *guid.New()
+	integrationID := *guid.New()
+	sut := NewUnitOfWork(pool)
+	_, err := sut.Rest().Sync(ctx, &domain.SnapshotRest{
+		IntegrationID: integrationID,
+		FilialID:      &filialID,
+		WarehouseID:   *guid.New(),
+		ProductID:     productID,
+		Quantity:      decimal.NewFromInt(1),
+	})
+	require.NoError(t, err)
+
+	err = sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		if _, err := work.Staging().Stage(ctx, domain.SnapshotRestsOf([]*domain.SnapshotRest{{
+			IntegrationID: integrationID,
+			FilialID:      &filialID,
+			WarehouseID:   *guid.New(),
+			ProductID:     productID,
+			Quantity:      decimal.NewFromInt(2),
+		}})); err != nil {
+			return err
+		}
+		_, err := work.Staging().Merge(ctx, *guid.New(), time.Now())
+		return err
+	})
+
+	assert.ErrorIs(t, err, domain.ErrIntegrationKeyMismatch)
+}
+
+func TestRestStagingDuplicateIntegrationID(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	staged := &domain.SnapshotRest{
+		IntegrationID: *guid.New(),
+		WarehouseID:   *guid.New(),
+		ProductID:     *guid.New(),
+		Quantity:      decimal.NewFromInt(1),
+	}
+
+	err := NewUnitOfWork(pool).Begin(ctx, func(work domain.UnitOfWork) error {
+		_, err := work.Staging().Stage(ctx, domain.SnapshotRestsOf([]*domain.SnapshotRest{staged, staged}))
+		return err
+	})
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateIntegrationID)
+}
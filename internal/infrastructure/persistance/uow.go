@@ -2,34 +2,107 @@ package persistance
 
 import (
 	"context"
+	"errors"
+
 	"github.com/DimKa163/stocks/internal/domain"
 	"github.com/DimKa163/stocks/internal/shared/db"
+	"github.com/jackc/pgx/v5"
 )
 
+var isoLevels = map[domain.IsolationLevel]pgx.TxIsoLevel{
+	domain.IsolationDefault:        "",
+	domain.IsolationReadCommitted:  pgx.ReadCommitted,
+	domain.IsolationRepeatableRead: pgx.RepeatableRead,
+	domain.IsolationSerializable:   pgx.Serializable,
+}
+
 type UnitOfWork struct {
-	db db.QueryExecutor
+	db    db.QueryExecutor
+	retry RetryPolicy
+}
+
+type Option func(*UnitOfWork)
+
+// WithRetry re-runs transactions failing with a serialization failure or a
+// deadlock according to the policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(u *UnitOfWork) {
+		u.retry = policy
+	}
 }
 
-func NewUnitOfWork(db db.QueryExecutor) *UnitOfWork {
-	return &UnitOfWork{db: db}
+func NewUnitOfWork(db db.QueryExecutor, opts ...Option) *UnitOfWork {
+	u := &UnitOfWork{db: db}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 func (u *UnitOfWork) Rest() domain.RestRepository {
 	return NewRestRepository(u.db)
 }
 
-func (u *UnitOfWork) Begin(ctx context.Context, fn func(work domain.UnitOfWork) error) error {
-	tx, err := u.db.Begin(ctx)
-	if err != nil {
-		return err
+func (u *UnitOfWork) Warehouse() domain.WarehouseRepository {
+	return NewWarehouseRepository(u.db)
+}
+
+func (u *UnitOfWork) Reservation() domain.ReservationRepository {
+	return NewReservationRepository(u.db)
+}
+
+func (u *UnitOfWork) Ledger() domain.LedgerRepository {
+	return NewLedgerRepository(u.db)
+}
+
+func (u *UnitOfWork) Staging() domain.RestStagingRepository {
+	return NewRestStagingRepository(u.db)
+}
+
+func (u *UnitOfWork) Outbox() domain.OutboxRepository {
+	return NewOutboxRepository(u.db)
+}
+
+// Begin retries the whole transaction when the retry policy allows it, so fn
+// must not have side effects outside of the transaction. Nested transactions
+// are never retried on their own, the outermost one is.
+func (u *UnitOfWork) Begin(ctx context.Context, fn func(work domain.UnitOfWork) error, opts ...domain.TxOption) error {
+	options := domain.NewTxOptions(opts...)
+	if _, ok := u.db.(db.TxBeginner); !ok {
+		return u.run(ctx, fn, options)
 	}
-	err = fn(NewUnitOfWork(tx))
+	return u.retry.do(ctx, func() error {
+		return u.run(ctx, fn, options)
+	})
+}
 
+func (u *UnitOfWork) run(ctx context.Context, fn func(work domain.UnitOfWork) error, options domain.TxOptions) (err error) {
+	tx, err := u.begin(ctx, options)
 	if err != nil {
-		if err := tx.Rollback(ctx); err != nil {
-			return err
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
 		}
-		return nil
+	}()
+	if err = fn(NewUnitOfWork(tx)); err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
 	}
 	return tx.Commit(ctx)
 }
+
+// begin starts a transaction with the options, or a savepoint when the unit
+// of work already runs in a transaction.
+func (u *UnitOfWork) begin(ctx context.Context, options domain.TxOptions) (pgx.Tx, error) {
+	beginner, ok := u.db.(db.TxBeginner)
+	if !ok {
+		return u.db.Begin(ctx)
+	}
+	txOptions := pgx.TxOptions{IsoLevel: isoLevels[options.Isolation]}
+	if options.ReadOnly {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+	return beginner.BeginTx(ctx, txOptions)
+}
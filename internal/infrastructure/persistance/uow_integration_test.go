@@ -0,0 +1,158 @@
+//go:build integration
+
+package persistance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/pgtest"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWorkCommit(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewUnitOfWork(pool)
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		_, err := work.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4))
+		return err
+	})
+
+	require.NoError(t, err)
+	rest, err := sut.Rest().Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "4", rest.Quantity.String())
+}
+
+func TestUnitOfWorkRollback(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewUnitOfWork(pool)
+
+	abort := errors.New("abort")
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		if _, err := work.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4)); err != nil {
+			return err
+		}
+		return abort
+	})
+
+	assert.ErrorIs(t, err, abort)
+	_, err = sut.Rest().Get(ctx, filialID, warehouseID, productID)
+	assert.ErrorIs(t, err, domain.ErrRestNotFound)
+}
+
+func TestUnitOfWorkPanic(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewUnitOfWork(pool)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = sut.Begin(ctx, func(work domain.UnitOfWork) error {
+			if _, err := work.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4)); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+
+	_, err := sut.Rest().Get(ctx, filialID, warehouseID, productID)
+	assert.ErrorIs(t, err, domain.ErrRestNotFound)
+	assert.Zero(t, pool.Stat().AcquiredConns())
+}
+
+func TestUnitOfWorkNested(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	sut := NewUnitOfWork(pool)
+	abort := errors.New("abort")
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		if _, err := work.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(4)); err != nil {
+			return err
+		}
+		err := work.Begin(ctx, func(nested domain.UnitOfWork) error {
+			if _, err := nested.Rest().Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(6)); err != nil {
+				return err
+			}
+			return abort
+		})
+		assert.ErrorIs(t, err, abort)
+		return nil
+	})
+
+	require.NoError(t, err)
+	rest, err := sut.Rest().Get(ctx, filialID, warehouseID, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "4", rest.Quantity.String())
+}
+
+func TestUnitOfWorkReadOnly(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	sut := NewUnitOfWork(pool)
+
+	err := sut.Begin(ctx, func(work domain.UnitOfWork) error {
+		_, err := work.Rest().Adjust(ctx, *guid.New(), *guid.New(), *guid.New(), decimal.NewFromInt(1))
+		return err
+	}, domain.ReadOnly(), domain.WithIsolation(domain.IsolationSerializable))
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	assert.Equal(t, "25006", pgErr.Code)
+}
+
+func TestRestRepositoryAdjustNegative(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(1))
+	sut := NewRestRepository(pool)
+
+	_, err := sut.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(-2))
+
+	assert.ErrorIs(t, err, domain.ErrNegativeRest)
+}
+
+func TestRestRepositoryAdjustBelowReserved(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	pgtest.SeedRest(t, pool, &filialID, warehouseID, productID, decimal.NewFromInt(5))
+	_, err := pool.Exec(ctx, `INSERT INTO public.reservation (id, order_id, filial_id, warehouse_id, product_id, quantity, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, now() + interval '1 hour')`, *guid.New(), *guid.New(), filialID, warehouseID, productID, decimal.NewFromInt(3))
+	require.NoError(t, err)
+	sut := NewRestRepository(pool)
+
+	_, err = sut.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(-3))
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+
+	rest, err := sut.Adjust(ctx, filialID, warehouseID, productID, decimal.NewFromInt(-2))
+	require.NoError(t, err)
+	assert.Equal(t, "3", rest.Quantity.String())
+}
@@ -0,0 +1,43 @@
+package persistance
+
+import (
+	"context"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/shared/db"
+	"github.com/beevik/guid"
+)
+
+const (
+	warehouseManyQuery = `SELECT id, type, descriptor_group, rest_available, pickup_only FROM public.warehouse
+				WHERE id = ANY($1)`
+)
+
+type WarehouseRepository struct {
+	db db.QueryExecutor
+}
+
+func NewWarehouseRepository(db db.QueryExecutor) *WarehouseRepository {
+	return &WarehouseRepository{db: db}
+}
+
+func (r *WarehouseRepository) GetMany(ctx context.Context, warehouseIDs []guid.Guid) ([]*domain.Warehouse, error) {
+	rows, err := r.db.Query(ctx, warehouseManyQuery, warehouseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	warehouses := make([]*domain.Warehouse, 0)
+	for rows.Next() {
+		var (
+			warehouse     domain.Warehouse
+			warehouseType int16
+		)
+		if err = rows.Scan(&warehouse.WarehouseID, &warehouseType, &warehouse.DescriptorGroup, &warehouse.RestAvailable, &warehouse.PickupOnly); err != nil {
+			return nil, err
+		}
+		warehouse.Type = domain.WarehouseType(warehouseType)
+		warehouses = append(warehouses, &warehouse)
+	}
+	return warehouses, rows.Err()
+}
@@ -0,0 +1,91 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+)
+
+const filePrefix = "file:"
+
+// message is the JSON line written for an event.
+type message struct {
+	Position  int64           `json:"position"`
+	EventID   string          `json:"event_id"`
+	Topic     string          `json:"topic"`
+	Key       string          `json:"key"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// WriterPublisher writes every event as a JSON line. It is meant for local
+// runs where no broker is available.
+type WriterPublisher struct {
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// Open returns a publisher for the target: "stdout" or "file:<path>". A file
+// is appended to and synced after every event.
+func Open(target string) (*WriterPublisher, error) {
+	switch {
+	case target == "stdout":
+		return NewWriterPublisher(os.Stdout), nil
+	case strings.HasPrefix(target, filePrefix):
+		path := strings.TrimPrefix(target, filePrefix)
+		if path == "" {
+			return nil, errors.New("publisher file path is empty")
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return &WriterPublisher{w: f, file: f}, nil
+	default:
+		return nil, fmt.Errorf("unknown publisher %q", target)
+	}
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event *domain.OutboxEvent) error {
+	line, err := json.Marshal(message{
+		Position:  event.Position,
+		EventID:   event.EventID.String(),
+		Topic:     event.Topic,
+		Key:       event.Key,
+		Payload:   event.Payload,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err = p.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if p.file != nil {
+		return p.file.Sync()
+	}
+	return nil
+}
+
+// Close closes the file opened by Open. It does nothing for other writers.
+func (p *WriterPublisher) Close() error {
+	if p.file == nil {
+		return nil
+	}
+	return p.file.Close()
+}
@@ -0,0 +1,51 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	event, err := domain.NewOutboxEvent("stock.moved", "doc", map[string]int{"n": 1}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	event.Position = 7
+	sut := NewWriterPublisher(&buf)
+
+	require.NoError(t, sut.Publish(context.Background(), event))
+
+	assert.JSONEq(t, `{"position":7,"event_id":"`+event.EventID.String()+`","topic":"stock.moved","key":"doc",
+		"payload":{"n":1},"created_at":"2025-01-01T00:00:00Z"}`, buf.String())
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n")))
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	event, err := domain.NewOutboxEvent("t", "k", nil, time.Now())
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		sut, err := Open("file:" + path)
+		require.NoError(t, err)
+		require.NoError(t, sut.Publish(context.Background(), event))
+		require.NoError(t, sut.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+}
+
+func TestOpenUnknown(t *testing.T) {
+	_, err := Open("kafka")
+	assert.Error(t, err)
+	_, err = Open("file:")
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+//go:build integration
+
+package pgtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// SeedWarehouse inserts the warehouse metadata.
+func SeedWarehouse(t *testing.T, pool *pgxpool.Pool, warehouse *domain.Warehouse) {
+	t.Helper()
+	_, err := pool.Exec(context.Background(), `INSERT INTO public.warehouse (id, type, descriptor_group, rest_available, pickup_only)
+		VALUES ($1, $2, $3, $4, $5)`,
+		warehouse.WarehouseID, int16(warehouse.Type), warehouse.DescriptorGroup, warehouse.RestAvailable, warehouse.PickupOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// SeedRest inserts a rest and returns its id.
+func SeedRest(t *testing.T, pool *pgxpool.Pool, filialID *guid.Guid, warehouseID, productID guid.Guid, quantity decimal.Decimal) guid.Guid {
+	t.Helper()
+	restID := *guid.New()
+	_, err := pool.Exec(context.Background(), `INSERT INTO public.rest (id, filial_id, warehouse_id, product_id, quantity)
+		VALUES ($1, $2, $3, $4, $5)`, restID, filialID, warehouseID, productID, quantity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return restID
+}
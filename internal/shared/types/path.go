@@ -1,9 +1,23 @@
 package types
 
-import "github.com/beevik/guid"
+import (
+	"sort"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+// NodeCost is what shipping one unit from a node costs and how long the
+// delivery from it takes.
+type NodeCost struct {
+	Cost     decimal.Decimal
+	LeadTime time.Duration
+}
 
 type Path struct {
 	nodes  []guid.Guid
+	costs  map[guid.Guid]NodeCost
 	length int
 }
 
@@ -48,6 +62,32 @@ func (p *Path) ForeachReverse(fn func(node guid.Guid) (bool, error)) error {
 	return nil
 }
 
+// ForeachCheapest walks the nodes from the lowest unit cost up, nodes with
+// the same cost in path order. Nodes without a cost are walked last, their
+// cost is unknown rather than free.
+func (p *Path) ForeachCheapest(fn func(node guid.Guid) (bool, error)) error {
+	nodes := make([]guid.Guid, p.length)
+	copy(nodes, p.nodes[:p.length])
+	sort.SliceStable(nodes, func(i, j int) bool {
+		ci, iok := p.costs[nodes[i]]
+		cj, jok := p.costs[nodes[j]]
+		if iok != jok {
+			return iok
+		}
+		return ci.Cost.LessThan(cj.Cost)
+	})
+	for _, node := range nodes {
+		cont, err := fn(node)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			break
+		}
+	}
+	return nil
+}
+
 func (p *Path) AddNode(n guid.Guid) {
 	if len(p.nodes) > p.length {
 		p.nodes[p.length] = n
@@ -58,6 +98,29 @@ func (p *Path) AddNode(n guid.Guid) {
 	}
 }
 
+func (p *Path) Nodes() []guid.Guid {
+	return p.nodes[:p.length]
+}
+
 func (p *Path) Len() int {
 	return p.length
 }
+
+func (p *Path) AddNodeWithCost(n guid.Guid, cost NodeCost) {
+	p.AddNode(n)
+	if p.costs == nil {
+		p.costs = make(map[guid.Guid]NodeCost)
+	}
+	p.costs[n] = cost
+}
+
+// Cost returns the cost of the node, zero for nodes added without one.
+func (p *Path) Cost(n guid.Guid) NodeCost {
+	return p.costs[n]
+}
+
+// LookupCost returns the cost of the node and whether the node has one.
+func (p *Path) LookupCost(n guid.Guid) (NodeCost, bool) {
+	cost, ok := p.costs[n]
+	return cost, ok
+}
@@ -0,0 +1,275 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/inventory"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	stocksv1 "github.com/DimKa163/stocks/pkg/api/stocks/v1"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func (s *Server) Inventory(ctx context.Context, req *stocksv1.InventoryRequest) (*stocksv1.InventoryResponse, error) {
+	items, path, err := inventoryRequestToModel(req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.inventoryService.Inventory(ctx, items, path, req.GetPickup())
+	if err != nil {
+		switch {
+		case errors.Is(err, inventory.ErrNoAvailableNodes):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, domain.ErrLockNotAvailable):
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return newInventoryResponse(state), nil
+}
+
+func inventoryRequestToModel(req *stocksv1.InventoryRequest) ([]models.DeliveryItemer, *types.Path, error) {
+	if len(req.GetPath()) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "path: must not be empty")
+	}
+	costs, err := nodeCostsToModel(req.GetNodeCosts())
+	if err != nil {
+		return nil, nil, err
+	}
+	path := types.NewPath(len(req.GetPath()))
+	for i, node := range req.GetPath() {
+		warehouseID, err := parseGuid(fmt.Sprintf("path[%d]", i), node)
+		if err != nil {
+			return nil, nil, err
+		}
+		cost, ok := costs[warehouseID]
+		if !ok {
+			path.AddNode(warehouseID)
+			continue
+		}
+		path.AddNodeWithCost(warehouseID, cost)
+		delete(costs, warehouseID)
+	}
+	for warehouseID := range costs {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "node_costs: warehouse %s is not on the path", warehouseID.String())
+	}
+	if len(req.GetItems()) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "items: must not be empty")
+	}
+	items := make([]models.DeliveryItemer, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		field := fmt.Sprintf("items[%d]", i)
+		var di models.DeliveryItemer
+		switch it := item.GetItem().(type) {
+		case *stocksv1.DeliveryItem_Simple:
+			di, err = simpleProductToModel(field+".simple", it.Simple, "")
+		case *stocksv1.DeliveryItem_Composite:
+			di, err = compositeProductToModel(field+".composite", it.Composite)
+		default:
+			err = status.Errorf(codes.InvalidArgument, "%s: item is not set", field)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		items[i] = di
+	}
+	return items, path, nil
+}
+
+func nodeCostsToModel(nodeCosts []*stocksv1.NodeCost) (map[guid.Guid]types.NodeCost, error) {
+	costs := make(map[guid.Guid]types.NodeCost, len(nodeCosts))
+	for i, nodeCost := range nodeCosts {
+		field := fmt.Sprintf("node_costs[%d]", i)
+		warehouseID, err := parseGuid(field+".warehouse_id", nodeCost.GetWarehouseId())
+		if err != nil {
+			return nil, err
+		}
+		cost := decimal.Zero
+		if nodeCost.GetCost() != "" {
+			if cost, err = decimal.NewFromString(nodeCost.GetCost()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%s.cost: %v", field, err)
+			}
+			if cost.IsNegative() {
+				return nil, status.Errorf(codes.InvalidArgument, "%s.cost: must not be negative", field)
+			}
+		}
+		leadTime, err := durationToModel(field+".lead_time", nodeCost.GetLeadTime())
+		if err != nil {
+			return nil, err
+		}
+		costs[warehouseID] = types.NodeCost{Cost: cost, LeadTime: leadTime}
+	}
+	return costs, nil
+}
+
+// durationToModel converts an optional non-negative duration.
+func durationToModel(field string, d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "%s: %v", field, err)
+	}
+	if d.AsDuration() < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s: must not be negative", field)
+	}
+	return d.AsDuration(), nil
+}
+
+func simpleProductToModel(field string, p *stocksv1.SimpleProduct, parentFilialID string) (*models.SimpleProduct, error) {
+	productID, err := parseGuid(field+".product_id", p.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	quantity, err := parseQuantity(field+".quantity", p.GetQuantity())
+	if err != nil {
+		return nil, err
+	}
+	filial := p.GetFilialId()
+	if filial == "" {
+		filial = parentFilialID
+	}
+	filialID, err := parseGuid(field+".filial_id", filial)
+	if err != nil {
+		return nil, err
+	}
+	choice, err := choicePriorityToModel(field+".choice_priority", p.GetChoicePriority())
+	if err != nil {
+		return nil, err
+	}
+	product := models.NewSimpleProduct(productID, quantity, p.GetIsLocal(), choice, filialID)
+	if product.Deadline, err = durationToModel(field+".deadline", p.GetDeadline()); err != nil {
+		return nil, err
+	}
+	if product.IgnoredNodes, err = ignoredNodesToModel(field, p.GetIgnoredNodes()); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func ignoredNodesToModel(field string, ignoredNodes []string) ([]guid.Guid, error) {
+	var nodes []guid.Guid
+	for i, node := range ignoredNodes {
+		warehouseID, err := parseGuid(fmt.Sprintf("%s.ignored_nodes[%d]", field, i), node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, warehouseID)
+	}
+	return nodes, nil
+}
+
+func compositeProductToModel(field string, p *stocksv1.CompositeProduct) (*models.CompositeProduct, error) {
+	if len(p.GetProducts()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s.products: must not be empty", field)
+	}
+	filialID, err := parseGuid(field+".filial_id", p.GetFilialId())
+	if err != nil {
+		return nil, err
+	}
+	choice, err := choicePriorityToModel(field+".choice_priority", p.GetChoicePriority())
+	if err != nil {
+		return nil, err
+	}
+	// Nodes ignored by the composite are ignored by every product of it and
+	// its deadline applies to products without a stricter one.
+	ignored, err := ignoredNodesToModel(field, p.GetIgnoredNodes())
+	if err != nil {
+		return nil, err
+	}
+	deadline, err := durationToModel(field+".deadline", p.GetDeadline())
+	if err != nil {
+		return nil, err
+	}
+	products := make([]*models.SimpleProduct, len(p.GetProducts()))
+	for i, product := range p.GetProducts() {
+		sp, err := simpleProductToModel(fmt.Sprintf("%s.products[%d]", field, i), product, p.GetFilialId())
+		if err != nil {
+			return nil, err
+		}
+		sp.IgnoredNodes = append(sp.IgnoredNodes, ignored...)
+		if deadline > 0 && (sp.Deadline == 0 || deadline < sp.Deadline) {
+			sp.Deadline = deadline
+		}
+		products[i] = sp
+	}
+	return models.NewCompositeProduct(products, choice, filialID), nil
+}
+
+func choicePriorityToModel(field string, cp stocksv1.ChoicePriority) (models.ChoicePriority, error) {
+	switch cp {
+	case stocksv1.ChoicePriority_CHOICE_PRIORITY_UNSPECIFIED, stocksv1.ChoicePriority_CHOICE_PRIORITY_NEAREST:
+		return models.Nearest, nil
+	case stocksv1.ChoicePriority_CHOICE_PRIORITY_FARTHEST:
+		return models.Farthest, nil
+	case stocksv1.ChoicePriority_CHOICE_PRIORITY_FEWEST_WAREHOUSES:
+		return models.FewestWarehouses, nil
+	case stocksv1.ChoicePriority_CHOICE_PRIORITY_CHEAPEST:
+		return models.Cheapest, nil
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "%s: unknown choice priority %d", field, cp)
+}
+
+func inventoryResultFromModel(ir models.InventoryResult) stocksv1.InventoryResult {
+	switch ir {
+	case models.AllInStockAtOne:
+		return stocksv1.InventoryResult_INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE
+	case models.AllInStockAtSeveral:
+		return stocksv1.InventoryResult_INVENTORY_RESULT_ALL_IN_STOCK_AT_SEVERAL
+	case models.PartiallyInStock:
+		return stocksv1.InventoryResult_INVENTORY_RESULT_PARTIALLY_IN_STOCK
+	case models.AllToProduce:
+		return stocksv1.InventoryResult_INVENTORY_RESULT_ALL_TO_PRODUCE
+	}
+	return stocksv1.InventoryResult_INVENTORY_RESULT_UNSPECIFIED
+}
+
+func skipReasonFromModel(sr models.SkipReason) stocksv1.SkipReason {
+	switch sr {
+	case models.SkipReasonIgnored:
+		return stocksv1.SkipReason_SKIP_REASON_IGNORED
+	case models.SkipReasonRestUnavailable:
+		return stocksv1.SkipReason_SKIP_REASON_REST_UNAVAILABLE
+	case models.SkipReasonPickupOnly:
+		return stocksv1.SkipReason_SKIP_REASON_PICKUP_ONLY
+	case models.SkipReasonLeadTime:
+		return stocksv1.SkipReason_SKIP_REASON_LEAD_TIME
+	}
+	return stocksv1.SkipReason_SKIP_REASON_UNSPECIFIED
+}
+
+func newInventoryResponse(state *models.InventoryState) *stocksv1.InventoryResponse {
+	resp := &stocksv1.InventoryResponse{
+		Result:       inventoryResultFromModel(state.Result),
+		StockStates:  make([]*stocksv1.StockState, len(state.StockStates)),
+		SkippedNodes: make([]*stocksv1.SkippedNode, len(state.SkippedNodes)),
+		Cost:         state.Cost.String(),
+		LeadTime:     durationpb.New(state.LeadTime),
+	}
+	for i, s := range state.StockStates {
+		resp.StockStates[i] = &stocksv1.StockState{
+			ProductId:   s.ProductID.String(),
+			Quantity:    s.Quantity.String(),
+			WarehouseId: optionalGuid(s.WarehouseID),
+			Produce:     s.Produce,
+			Cost:        s.Cost.String(),
+			LeadTime:    durationpb.New(s.LeadTime),
+		}
+	}
+	for i, s := range state.SkippedNodes {
+		resp.SkippedNodes[i] = &stocksv1.SkippedNode{
+			WarehouseId: s.WarehouseID.String(),
+			ProductId:   optionalGuid(s.ProductID),
+			Reason:      skipReasonFromModel(s.Reason),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,134 @@
+package grpcapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain/models"
+	stocksv1 "github.com/DimKa163/stocks/pkg/api/stocks/v1"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestInventoryRequestToModel(t *testing.T) {
+	productID := *guid.New()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	req := &stocksv1.InventoryRequest{
+		Items: []*stocksv1.DeliveryItem{
+			{Item: &stocksv1.DeliveryItem_Simple{Simple: &stocksv1.SimpleProduct{
+				ProductId:      productID.String(),
+				Quantity:       "2.5",
+				ChoicePriority: stocksv1.ChoicePriority_CHOICE_PRIORITY_FARTHEST,
+				FilialId:       filialID.String(),
+			}}},
+			{Item: &stocksv1.DeliveryItem_Composite{Composite: &stocksv1.CompositeProduct{
+				FilialId: filialID.String(),
+				Products: []*stocksv1.SimpleProduct{{ProductId: productID.String(), Quantity: "1", IsLocal: true}},
+			}}},
+		},
+		Path: []string{warehouseID.String()},
+		NodeCosts: []*stocksv1.NodeCost{
+			{WarehouseId: warehouseID.String(), Cost: "1.5", LeadTime: durationpb.New(24 * time.Hour)},
+		},
+	}
+
+	items, path, err := inventoryRequestToModel(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, path.Len())
+	assert.Equal(t, warehouseID, path.Destination())
+	assert.True(t, decimal.RequireFromString("1.5").Equal(path.Cost(warehouseID).Cost))
+	assert.Equal(t, 24*time.Hour, path.Cost(warehouseID).LeadTime)
+	assert.Equal(t, models.NewSimpleProduct(productID, decimal.RequireFromString("2.5"), false, models.Farthest, filialID), items[0])
+	assert.Equal(t, models.NewCompositeProduct([]*models.SimpleProduct{
+		models.NewSimpleProduct(productID, decimal.NewFromInt(1), true, models.Nearest, filialID),
+	}, models.Nearest, filialID), items[1])
+}
+
+func TestInventoryRequestToModelComposite(t *testing.T) {
+	filialID := *guid.New()
+	ignoredID := *guid.New()
+	productID1 := *guid.New()
+	productID2 := *guid.New()
+	req := &stocksv1.InventoryRequest{
+		Items: []*stocksv1.DeliveryItem{
+			{Item: &stocksv1.DeliveryItem_Composite{Composite: &stocksv1.CompositeProduct{
+				FilialId:     filialID.String(),
+				IgnoredNodes: []string{ignoredID.String()},
+				Deadline:     durationpb.New(24 * time.Hour),
+				Products: []*stocksv1.SimpleProduct{
+					{ProductId: productID1.String(), Quantity: "1", Deadline: durationpb.New(48 * time.Hour)},
+					{ProductId: productID2.String(), Quantity: "1", Deadline: durationpb.New(12 * time.Hour)},
+				},
+			}}},
+		},
+		Path: []string{guid.NewString()},
+	}
+
+	items, _, err := inventoryRequestToModel(req)
+
+	require.NoError(t, err)
+	composite := items[0].(*models.CompositeProduct)
+	assert.Equal(t, []guid.Guid{ignoredID}, composite.Products[0].IgnoredNodes)
+	assert.Equal(t, 24*time.Hour, composite.Products[0].Deadline)
+	assert.Equal(t, []guid.Guid{ignoredID}, composite.Products[1].IgnoredNodes)
+	assert.Equal(t, 12*time.Hour, composite.Products[1].Deadline)
+
+	req.Items[0].GetComposite().IgnoredNodes = []string{"nope"}
+	_, _, err = inventoryRequestToModel(req)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInventoryRequestToModelInvalid(t *testing.T) {
+	_, _, err := inventoryRequestToModel(&stocksv1.InventoryRequest{
+		Items: []*stocksv1.DeliveryItem{{Item: &stocksv1.DeliveryItem_Simple{Simple: &stocksv1.SimpleProduct{
+			ProductId: guid.NewString(),
+			Quantity:  "abc",
+			FilialId:  guid.NewString(),
+		}}}},
+		Path: []string{guid.NewString()},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, _, err = inventoryRequestToModel(&stocksv1.InventoryRequest{
+		Items: []*stocksv1.DeliveryItem{{Item: &stocksv1.DeliveryItem_Simple{Simple: &stocksv1.SimpleProduct{
+			ProductId: guid.NewString(),
+			Quantity:  "1",
+			FilialId:  guid.NewString(),
+		}}}},
+		Path:      []string{guid.NewString()},
+		NodeCosts: []*stocksv1.NodeCost{{WarehouseId: guid.NewString(), Cost: "1"}},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNewInventoryResponse(t *testing.T) {
+	productID := *guid.New()
+	warehouseID := *guid.New()
+	resp := newInventoryResponse(&models.InventoryState{
+		Result:   models.AllInStockAtOne,
+		Cost:     decimal.NewFromInt(3),
+		LeadTime: time.Hour,
+		StockStates: []*models.StockState{
+			{ProductID: productID, Quantity: decimal.RequireFromString("0.333333333333333333333"), WarehouseID: &warehouseID,
+				Cost: decimal.NewFromInt(3), LeadTime: time.Hour},
+		},
+	})
+
+	assert.Equal(t, stocksv1.InventoryResult_INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE, resp.GetResult())
+	assert.Equal(t, "0.333333333333333333333", resp.GetStockStates()[0].GetQuantity())
+	assert.Equal(t, warehouseID.String(), resp.GetStockStates()[0].GetWarehouseId())
+	assert.Equal(t, "3", resp.GetStockStates()[0].GetCost())
+	assert.Equal(t, time.Hour, resp.GetStockStates()[0].GetLeadTime().AsDuration())
+	assert.Equal(t, "3", resp.GetCost())
+	assert.Equal(t, time.Hour, resp.GetLeadTime().AsDuration())
+}
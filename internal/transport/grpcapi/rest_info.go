@@ -0,0 +1,108 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/domain"
+	stocksv1 "github.com/DimKa163/stocks/pkg/api/stocks/v1"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) GetStockOneItemInfo(ctx context.Context, req *stocksv1.GetStockOneItemInfoRequest) (*stocksv1.GetStockOneItemInfoResponse, error) {
+	filialID, shipmentID, err := parseStockLocation(req.GetFilialId(), req.GetShipmentId())
+	if err != nil {
+		return nil, err
+	}
+	product, err := requestedProductToModel("product", req.GetProduct())
+	if err != nil {
+		return nil, err
+	}
+	stockInfo, err := s.restInfoService.GetStockOneItemInfo(ctx, product, filialID, shipmentID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &stocksv1.GetStockOneItemInfoResponse{
+		InStock:     stockInfo.InStock,
+		ProductInfo: productInfoFromModel(stockInfo.ProductInfo),
+	}, nil
+}
+
+func (s *Server) GetStockManyItemsInfo(ctx context.Context, req *stocksv1.GetStockManyItemsInfoRequest) (*stocksv1.GetStockManyItemsInfoResponse, error) {
+	filialID, shipmentID, err := parseStockLocation(req.GetFilialId(), req.GetShipmentId())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetProducts()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "products: must not be empty")
+	}
+	products := make([]info.RequestedProduct, len(req.GetProducts()))
+	for i, p := range req.GetProducts() {
+		product, err := requestedProductToModel(fmt.Sprintf("products[%d]", i), p)
+		if err != nil {
+			return nil, err
+		}
+		products[i] = product
+	}
+	stockInfo, err := s.restInfoService.GetStockManyItemsInfo(ctx, products, filialID, shipmentID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &stocksv1.GetStockManyItemsInfoResponse{
+		InStock:     stockInfo.InStock,
+		ProductInfo: make([]*stocksv1.ProductInfo, len(stockInfo.ProductInfo)),
+	}
+	for i, p := range stockInfo.ProductInfo {
+		resp.ProductInfo[i] = productInfoFromModel(p)
+	}
+	return resp, nil
+}
+
+func parseStockLocation(filial, shipment string) (guid.Guid, guid.Guid, error) {
+	filialID, err := parseGuid("filial_id", filial)
+	if err != nil {
+		return guid.Guid{}, guid.Guid{}, err
+	}
+	shipmentID, err := parseGuid("shipment_id", shipment)
+	if err != nil {
+		return guid.Guid{}, guid.Guid{}, err
+	}
+	return filialID, shipmentID, nil
+}
+
+func requestedProductToModel(field string, p *stocksv1.RequestedProduct) (info.RequestedProduct, error) {
+	productID, err := parseGuid(field+".product_id", p.GetProductId())
+	if err != nil {
+		return info.RequestedProduct{}, err
+	}
+	quantity, err := parseQuantity(field+".quantity", p.GetQuantity())
+	if err != nil {
+		return info.RequestedProduct{}, err
+	}
+	return info.RequestedProduct{ProductID: productID, Quantity: quantity}, nil
+}
+
+func productInfoFromModel(p info.ProductInfo) *stocksv1.ProductInfo {
+	return &stocksv1.ProductInfo{
+		ProductId: p.ProductID.String(),
+		Rest:      restFromModel(p.Rest),
+		Covered:   p.Covered,
+	}
+}
+
+func restFromModel(rest *domain.Rest) *stocksv1.Rest {
+	if rest == nil {
+		return nil
+	}
+	return &stocksv1.Rest{
+		RestId:        rest.RestID.String(),
+		FilialId:      optionalGuid(rest.FilialID),
+		IntegrationId: optionalGuid(rest.IntegrationID),
+		Quantity:      rest.Quantity.String(),
+		ProductId:     rest.ProductID.String(),
+		WarehouseId:   rest.WarehouseID.String(),
+	}
+}
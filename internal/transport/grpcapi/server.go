@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/application/inventory"
+	stocksv1 "github.com/DimKa163/stocks/pkg/api/stocks/v1"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	stocksv1.UnimplementedInventoryServiceServer
+	stocksv1.UnimplementedRestInfoServiceServer
+	inventoryService inventory.InventoryService
+	restInfoService  info.RestInfoService
+}
+
+func NewServer(inventoryService inventory.InventoryService, restInfoService info.RestInfoService) *Server {
+	return &Server{inventoryService: inventoryService, restInfoService: restInfoService}
+}
+
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	stocksv1.RegisterInventoryServiceServer(registrar, s)
+	stocksv1.RegisterRestInfoServiceServer(registrar, s)
+}
+
+func parseGuid(field, s string) (guid.Guid, error) {
+	g, err := guid.ParseString(s)
+	if err != nil {
+		return guid.Guid{}, status.Errorf(codes.InvalidArgument, "%s: %v", field, err)
+	}
+	return *g, nil
+}
+
+func parseQuantity(field, s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, status.Errorf(codes.InvalidArgument, "%s: %v", field, err)
+	}
+	if !d.IsPositive() {
+		return decimal.Decimal{}, status.Errorf(codes.InvalidArgument, "%s: must be positive", field)
+	}
+	return d, nil
+}
+
+func optionalGuid(g *guid.Guid) *string {
+	if g == nil {
+		return nil
+	}
+	s := g.String()
+	return &s
+}
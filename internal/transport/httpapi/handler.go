@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/application/ingest"
+	"github.com/DimKa163/stocks/internal/application/inventory"
+	"github.com/DimKa163/stocks/internal/application/movement"
+	"github.com/DimKa163/stocks/internal/application/reservation"
+	"github.com/beevik/guid"
+)
+
+type Handler struct {
+	inventoryService   inventory.InventoryService
+	restInfoService    info.RestInfoService
+	reservationService reservation.ReservationService
+	movementService    movement.MovementService
+	ingestService      ingest.IngestService
+}
+
+func NewHandler(
+	inventoryService inventory.InventoryService,
+	restInfoService info.RestInfoService,
+	reservationService reservation.ReservationService,
+	movementService movement.MovementService,
+	ingestService ingest.IngestService) *Handler {
+	return &Handler{
+		inventoryService:   inventoryService,
+		restInfoService:    restInfoService,
+		reservationService: reservationService,
+		movementService:    movementService,
+		ingestService:      ingestService,
+	}
+}
+
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/inventory", h.inventory)
+	mux.HandleFunc("POST /v1/stock-info", h.stockInfo)
+	mux.HandleFunc("POST /v1/stock-info/batch", h.stockInfoBatch)
+	mux.HandleFunc("POST /v1/reservations", h.reserve)
+	mux.HandleFunc("POST /v1/reservations/{order_id}/confirm", h.confirmReservation)
+	mux.HandleFunc("POST /v1/reservations/{order_id}/release", h.releaseReservation)
+	mux.HandleFunc("POST /v1/movements/receipts", h.receipt)
+	mux.HandleFunc("POST /v1/movements/shipments", h.shipment)
+	mux.HandleFunc("POST /v1/movements/write-offs", h.writeOff)
+	mux.HandleFunc("POST /v1/movements/transfers", h.transfer)
+	mux.HandleFunc("GET /v1/rests/as-of", h.restAsOf)
+	mux.HandleFunc("POST /v1/rest-snapshots", h.ingestSnapshot)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func parseGuid(field, s string) (guid.Guid, error) {
+	g, err := guid.ParseString(s)
+	if err != nil {
+		return guid.Guid{}, &fieldError{field: field, err: err}
+	}
+	return *g, nil
+}
+
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.field + ": " + e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,295 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/inventory"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	itemTypeSimple    = "simple"
+	itemTypeComposite = "composite"
+)
+
+type inventoryRequest struct {
+	Items     []inventoryItem `json:"items"`
+	Path      []string        `json:"path"`
+	NodeCosts []nodeCostJSON  `json:"node_costs,omitempty"`
+	Pickup    bool            `json:"pickup"`
+}
+
+// nodeCostJSON is the unit cost and the lead time, e.g. "48h", of a path node.
+type nodeCostJSON struct {
+	WarehouseID string          `json:"warehouse_id"`
+	Cost        decimal.Decimal `json:"cost"`
+	LeadTime    string          `json:"lead_time,omitempty"`
+}
+
+type inventoryItem struct {
+	Type           string          `json:"type"`
+	ProductID      string          `json:"product_id,omitempty"`
+	Quantity       decimal.Decimal `json:"quantity"`
+	IsLocal        bool            `json:"is_local,omitempty"`
+	ChoicePriority string          `json:"choice_priority,omitempty"`
+	FilialID       string          `json:"filial_id,omitempty"`
+	IgnoredNodes   []string        `json:"ignored_nodes,omitempty"`
+	Deadline       string          `json:"deadline,omitempty"`
+	Products       []inventoryItem `json:"products,omitempty"`
+}
+
+type inventoryResponse struct {
+	Result       string            `json:"result"`
+	StockStates  []stockStateJSON  `json:"stock_states"`
+	SkippedNodes []skippedNodeJSON `json:"skipped_nodes"`
+	Cost         decimal.Decimal   `json:"cost"`
+	LeadTime     string            `json:"lead_time,omitempty"`
+}
+
+type stockStateJSON struct {
+	ProductID   string          `json:"product_id"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	WarehouseID *string         `json:"warehouse_id,omitempty"`
+	Produce     bool            `json:"produce"`
+	Cost        decimal.Decimal `json:"cost"`
+	LeadTime    string          `json:"lead_time,omitempty"`
+}
+
+type skippedNodeJSON struct {
+	WarehouseID string  `json:"warehouse_id"`
+	ProductID   *string `json:"product_id,omitempty"`
+	Reason      string  `json:"reason"`
+}
+
+func (h *Handler) inventory(w http.ResponseWriter, r *http.Request) {
+	var req inventoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, path, err := req.toModel()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	state, err := h.inventoryService.Inventory(r.Context(), items, path, req.Pickup)
+	if err != nil {
+		switch {
+		case errors.Is(err, inventory.ErrNoAvailableNodes):
+			writeError(w, http.StatusUnprocessableEntity, err)
+		case errors.Is(err, domain.ErrLockNotAvailable):
+			writeError(w, http.StatusConflict, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, newInventoryResponse(state))
+}
+
+func (req *inventoryRequest) toModel() ([]models.DeliveryItemer, *types.Path, error) {
+	if len(req.Path) == 0 {
+		return nil, nil, errors.New("path: must not be empty")
+	}
+	costs, err := req.nodeCosts()
+	if err != nil {
+		return nil, nil, err
+	}
+	path := types.NewPath(len(req.Path))
+	for i, node := range req.Path {
+		warehouseID, err := parseGuid(fmt.Sprintf("path[%d]", i), node)
+		if err != nil {
+			return nil, nil, err
+		}
+		cost, ok := costs[warehouseID]
+		if !ok {
+			path.AddNode(warehouseID)
+			continue
+		}
+		path.AddNodeWithCost(warehouseID, cost)
+		delete(costs, warehouseID)
+	}
+	for warehouseID := range costs {
+		return nil, nil, fmt.Errorf("node_costs: warehouse %s is not on the path", warehouseID.String())
+	}
+	if len(req.Items) == 0 {
+		return nil, nil, errors.New("items: must not be empty")
+	}
+	items := make([]models.DeliveryItemer, len(req.Items))
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		var di models.DeliveryItemer
+		switch item.Type {
+		case itemTypeSimple, "":
+			di, err = item.toSimple(field, "")
+		case itemTypeComposite:
+			di, err = item.toComposite(field)
+		default:
+			err = fmt.Errorf("%s.type: unknown item type %q", field, item.Type)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		items[i] = di
+	}
+	return items, path, nil
+}
+
+func (req *inventoryRequest) nodeCosts() (map[guid.Guid]types.NodeCost, error) {
+	costs := make(map[guid.Guid]types.NodeCost, len(req.NodeCosts))
+	for i, nodeCost := range req.NodeCosts {
+		field := fmt.Sprintf("node_costs[%d]", i)
+		warehouseID, err := parseGuid(field+".warehouse_id", nodeCost.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		if nodeCost.Cost.IsNegative() {
+			return nil, fmt.Errorf("%s.cost: must not be negative", field)
+		}
+		leadTime, err := parseDuration(field+".lead_time", nodeCost.LeadTime)
+		if err != nil {
+			return nil, err
+		}
+		costs[warehouseID] = types.NodeCost{Cost: nodeCost.Cost, LeadTime: leadTime}
+	}
+	return costs, nil
+}
+
+// parseDuration parses an optional non-negative duration such as "36h".
+func parseDuration(field string, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", field, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s: must not be negative", field)
+	}
+	return d, nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// toSimple builds a simple product. Products nested in a composite inherit
+// the composite's filial, which is passed as parentFilialID.
+func (item *inventoryItem) toSimple(field string, parentFilialID string) (*models.SimpleProduct, error) {
+	productID, err := parseGuid(field+".product_id", item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%s.quantity: must be positive", field)
+	}
+	filial := item.FilialID
+	if filial == "" {
+		filial = parentFilialID
+	}
+	filialID, err := parseGuid(field+".filial_id", filial)
+	if err != nil {
+		return nil, err
+	}
+	choice, err := models.ParseChoicePriority(item.ChoicePriority)
+	if err != nil {
+		return nil, fmt.Errorf("%s.choice_priority: %w", field, err)
+	}
+	product := models.NewSimpleProduct(productID, item.Quantity, item.IsLocal, choice, filialID)
+	if product.Deadline, err = parseDuration(field+".deadline", item.Deadline); err != nil {
+		return nil, err
+	}
+	if product.IgnoredNodes, err = item.ignoredNodes(field); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (item *inventoryItem) ignoredNodes(field string) ([]guid.Guid, error) {
+	var nodes []guid.Guid
+	for i, node := range item.IgnoredNodes {
+		warehouseID, err := parseGuid(fmt.Sprintf("%s.ignored_nodes[%d]", field, i), node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, warehouseID)
+	}
+	return nodes, nil
+}
+
+func (item *inventoryItem) toComposite(field string) (*models.CompositeProduct, error) {
+	if len(item.Products) == 0 {
+		return nil, fmt.Errorf("%s.products: must not be empty", field)
+	}
+	filialID, err := parseGuid(field+".filial_id", item.FilialID)
+	if err != nil {
+		return nil, err
+	}
+	choice, err := models.ParseChoicePriority(item.ChoicePriority)
+	if err != nil {
+		return nil, fmt.Errorf("%s.choice_priority: %w", field, err)
+	}
+	// Nodes ignored by the composite are ignored by every product of it and
+	// its deadline applies to products without a stricter one.
+	ignored, err := item.ignoredNodes(field)
+	if err != nil {
+		return nil, err
+	}
+	deadline, err := parseDuration(field+".deadline", item.Deadline)
+	if err != nil {
+		return nil, err
+	}
+	products := make([]*models.SimpleProduct, len(item.Products))
+	for i, p := range item.Products {
+		product, err := p.toSimple(fmt.Sprintf("%s.products[%d]", field, i), item.FilialID)
+		if err != nil {
+			return nil, err
+		}
+		product.IgnoredNodes = append(product.IgnoredNodes, ignored...)
+		if deadline > 0 && (product.Deadline == 0 || deadline < product.Deadline) {
+			product.Deadline = deadline
+		}
+		products[i] = product
+	}
+	return models.NewCompositeProduct(products, choice, filialID), nil
+}
+
+func newInventoryResponse(state *models.InventoryState) *inventoryResponse {
+	resp := &inventoryResponse{
+		Result:       state.Result.String(),
+		StockStates:  make([]stockStateJSON, len(state.StockStates)),
+		SkippedNodes: make([]skippedNodeJSON, len(state.SkippedNodes)),
+		Cost:         state.Cost,
+		LeadTime:     formatDuration(state.LeadTime),
+	}
+	for i, s := range state.StockStates {
+		resp.StockStates[i] = stockStateJSON{
+			ProductID: s.ProductID.String(),
+			Quantity:  s.Quantity,
+			Produce:   s.Produce,
+			Cost:      s.Cost,
+			LeadTime:  formatDuration(s.LeadTime),
+		}
+		resp.StockStates[i].WarehouseID = optionalGuid(s.WarehouseID)
+	}
+	for i, s := range state.SkippedNodes {
+		resp.SkippedNodes[i] = skippedNodeJSON{
+			WarehouseID: s.WarehouseID.String(),
+			ProductID:   optionalGuid(s.ProductID),
+			Reason:      s.Reason.String(),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/DimKa163/stocks/internal/shared/types"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inventoryServiceStub struct {
+	items  []models.DeliveryItemer
+	path   *types.Path
+	pickup bool
+	state  *models.InventoryState
+}
+
+func (s *inventoryServiceStub) Inventory(_ context.Context, items []models.DeliveryItemer, path *types.Path, pickup bool) (*models.InventoryState, error) {
+	s.items = items
+	s.path = path
+	s.pickup = pickup
+	return s.state, nil
+}
+
+func TestInventory(t *testing.T) {
+	productID := *guid.New()
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	ignoredID := *guid.New()
+	quantity, _ := decimal.NewFromString("1.000000000000000000001")
+	stub := &inventoryServiceStub{state: &models.InventoryState{
+		Result:   models.PartiallyInStock,
+		Cost:     decimal.NewFromInt(5),
+		LeadTime: 48 * time.Hour,
+		StockStates: []*models.StockState{
+			{ProductID: productID, Quantity: quantity, WarehouseID: &warehouseID, Cost: decimal.NewFromInt(5), LeadTime: 48 * time.Hour},
+			{ProductID: productID, Quantity: decimal.NewFromInt(2), Produce: true},
+		},
+		SkippedNodes: []*models.SkippedNode{
+			{WarehouseID: ignoredID, ProductID: &productID, Reason: models.SkipReasonIgnored},
+		},
+	}}
+	mux := http.NewServeMux()
+	NewHandler(stub, nil, nil, nil, nil).Register(mux)
+
+	body := `{"items":[
+		{"type":"simple","product_id":"` + productID.String() + `","quantity":"3.000000000000000000001","filial_id":"` + filialID.String() + `","choice_priority":"cheapest","ignored_nodes":["` + ignoredID.String() + `"],"deadline":"72h"},
+		{"type":"composite","filial_id":"` + filialID.String() + `","ignored_nodes":["` + ignoredID.String() + `"],"deadline":"24h","products":[{"product_id":"` + productID.String() + `","quantity":1,"is_local":true,"deadline":"48h"}]}
+	],"path":["` + warehouseID.String() + `"],"node_costs":[{"warehouse_id":"` + warehouseID.String() + `","cost":"2.5","lead_time":"48h"}],"pickup":true}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/inventory", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"result":"PartiallyInStock","cost":"5","lead_time":"48h0m0s","stock_states":[
+		{"product_id":"`+productID.String()+`","quantity":"1.000000000000000000001","warehouse_id":"`+warehouseID.String()+`","produce":false,"cost":"5","lead_time":"48h0m0s"},
+		{"product_id":"`+productID.String()+`","quantity":"2","produce":true,"cost":"0"}
+	],"skipped_nodes":[
+		{"warehouse_id":"`+ignoredID.String()+`","product_id":"`+productID.String()+`","reason":"Ignored"}
+	]}`, rec.Body.String())
+
+	require.Len(t, stub.items, 2)
+	simple := stub.items[0].(*models.SimpleProduct)
+	assert.Equal(t, "3.000000000000000000001", simple.Quantity.String())
+	assert.Equal(t, models.Cheapest, simple.ChoicePriority)
+	assert.Equal(t, []guid.Guid{ignoredID}, simple.IgnoredNodes)
+	assert.Equal(t, 72*time.Hour, simple.Deadline)
+	composite := stub.items[1].(*models.CompositeProduct)
+	assert.Equal(t, filialID, composite.Products[0].FilialID)
+	assert.True(t, composite.Products[0].IsLocal)
+	assert.Equal(t, []guid.Guid{ignoredID}, composite.Products[0].IgnoredNodes)
+	assert.Equal(t, 24*time.Hour, composite.Products[0].Deadline)
+	assert.Equal(t, 1, stub.path.Len())
+	assert.Equal(t, types.NodeCost{Cost: decimal.RequireFromString("2.5"), LeadTime: 48 * time.Hour}, stub.path.Cost(warehouseID))
+	assert.True(t, stub.pickup)
+}
+
+func TestInventoryBadRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(&inventoryServiceStub{}, nil, nil, nil, nil).Register(mux)
+	cases := []struct {
+		Name string
+		Body string
+	}{
+		{Name: "malformed json", Body: `{`},
+		{Name: "empty path", Body: `{"items":[{"product_id":"` + guid.NewString() + `","quantity":"1","filial_id":"` + guid.NewString() + `"}],"path":[]}`},
+		{Name: "bad guid", Body: `{"items":[{"product_id":"nope","quantity":"1","filial_id":"` + guid.NewString() + `"}],"path":["` + guid.NewString() + `"]}`},
+		{Name: "unknown priority", Body: `{"items":[{"product_id":"` + guid.NewString() + `","quantity":"1","filial_id":"` + guid.NewString() + `","choice_priority":"random"}],"path":["` + guid.NewString() + `"]}`},
+		{Name: "node cost off the path", Body: `{"items":[{"product_id":"` + guid.NewString() + `","quantity":"1","filial_id":"` + guid.NewString() + `"}],"path":["` + guid.NewString() + `"],"node_costs":[{"warehouse_id":"` + guid.NewString() + `","cost":"1"}]}`},
+		{Name: "negative deadline", Body: `{"items":[{"product_id":"` + guid.NewString() + `","quantity":"1","filial_id":"` + guid.NewString() + `","deadline":"-1h"}],"path":["` + guid.NewString() + `"]}`},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/inventory", strings.NewReader(tt.Body)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/movement"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+type movementRequest struct {
+	DocumentID  string             `json:"document_id"`
+	FilialID    string             `json:"filial_id"`
+	WarehouseID string             `json:"warehouse_id"`
+	OrderID     *string            `json:"order_id"`
+	Lines       []movementLineJSON `json:"lines"`
+}
+
+type transferRequest struct {
+	DocumentID      string             `json:"document_id"`
+	FilialID        string             `json:"filial_id"`
+	FromWarehouseID string             `json:"from_warehouse_id"`
+	ToWarehouseID   string             `json:"to_warehouse_id"`
+	Lines           []movementLineJSON `json:"lines"`
+}
+
+type movementLineJSON struct {
+	ProductID string          `json:"product_id"`
+	Quantity  decimal.Decimal `json:"quantity"`
+}
+
+type movementResponse struct {
+	Movements []movementJSON `json:"movements"`
+}
+
+type movementJSON struct {
+	MovementID    string          `json:"movement_id"`
+	WarehouseID   string          `json:"warehouse_id"`
+	ProductID     string          `json:"product_id"`
+	IntegrationID *string         `json:"integration_id"`
+	Delta         decimal.Decimal `json:"delta"`
+	Reason        string          `json:"reason"`
+	DocumentID    string          `json:"document_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (h *Handler) receipt(w http.ResponseWriter, r *http.Request) {
+	documentID, filialID, warehouseID, orderID, lines, ok := decodeMovement(w, r)
+	if !ok || !withoutOrder(w, orderID) {
+		return
+	}
+	movements, err := h.movementService.Receive(r.Context(), documentID, filialID, warehouseID, lines)
+	writeMovements(w, movements, err)
+}
+
+// shipment fulfils the confirmed reservations of the order at the warehouse
+// when order_id is given.
+func (h *Handler) shipment(w http.ResponseWriter, r *http.Request) {
+	documentID, filialID, warehouseID, orderID, lines, ok := decodeMovement(w, r)
+	if !ok {
+		return
+	}
+	var (
+		movements []*domain.Movement
+		err       error
+	)
+	if orderID != nil {
+		movements, err = h.movementService.ShipOrder(r.Context(), documentID, *orderID, filialID, warehouseID, lines)
+	} else {
+		movements, err = h.movementService.Ship(r.Context(), documentID, filialID, warehouseID, domain.MovementReasonSHIPMENT, lines)
+	}
+	writeMovements(w, movements, err)
+}
+
+func (h *Handler) writeOff(w http.ResponseWriter, r *http.Request) {
+	documentID, filialID, warehouseID, orderID, lines, ok := decodeMovement(w, r)
+	if !ok || !withoutOrder(w, orderID) {
+		return
+	}
+	movements, err := h.movementService.Ship(r.Context(), documentID, filialID, warehouseID, domain.MovementReasonWRITEOFF, lines)
+	writeMovements(w, movements, err)
+}
+
+// withoutOrder rejects order_id on movements that cannot fulfil an order.
+func withoutOrder(w http.ResponseWriter, orderID *guid.Guid) bool {
+	if orderID != nil {
+		writeError(w, http.StatusBadRequest, errors.New("order_id: only shipments fulfil orders"))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) transfer(w http.ResponseWriter, r *http.Request) {
+	var req transferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	documentID, err := parseGuid("document_id", req.DocumentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filialID, err := parseGuid("filial_id", req.FilialID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	fromWarehouseID, err := parseGuid("from_warehouse_id", req.FromWarehouseID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	toWarehouseID, err := parseGuid("to_warehouse_id", req.ToWarehouseID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	lines, err := toLines(req.Lines)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	movements, err := h.movementService.Transfer(r.Context(), documentID, filialID, fromWarehouseID, toWarehouseID, lines)
+	writeMovements(w, movements, err)
+}
+
+// restAsOf reconstructs a rest from the ledger, e.g.
+// GET /v1/rests/as-of?filial_id=..&warehouse_id=..&product_id=..&at=2024-05-01T14:03:00Z
+func (h *Handler) restAsOf(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	// Without filial_id the rest that has no filial is reconstructed.
+	var filialID *guid.Guid
+	if s := query.Get("filial_id"); s != "" {
+		id, err := parseGuid("filial_id", s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filialID = &id
+	}
+	warehouseID, err := parseGuid("warehouse_id", query.Get("warehouse_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	productID, err := parseGuid("product_id", query.Get("product_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	at, err := time.Parse(time.RFC3339, query.Get("at"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, &fieldError{field: "at", err: err})
+		return
+	}
+	rest, err := h.movementService.RestAsOf(r.Context(), filialID, warehouseID, productID, at)
+	if err != nil {
+		if errors.Is(err, domain.ErrRestNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newRestJSON(rest))
+}
+
+func decodeMovement(w http.ResponseWriter, r *http.Request) (documentID, filialID, warehouseID guid.Guid, orderID *guid.Guid, lines []movement.Line, ok bool) {
+	var req movementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var err error
+	if documentID, err = parseGuid("document_id", req.DocumentID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if filialID, err = parseGuid("filial_id", req.FilialID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if warehouseID, err = parseGuid("warehouse_id", req.WarehouseID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.OrderID != nil {
+		id, err := parseGuid("order_id", *req.OrderID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		orderID = &id
+	}
+	if lines, err = toLines(req.Lines); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	return documentID, filialID, warehouseID, orderID, lines, true
+}
+
+func toLines(in []movementLineJSON) ([]movement.Line, error) {
+	lines := make([]movement.Line, len(in))
+	for i, l := range in {
+		productID, err := parseGuid(fmt.Sprintf("lines[%d].product_id", i), l.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = movement.Line{ProductID: productID, Quantity: l.Quantity}
+	}
+	return lines, nil
+}
+
+func writeMovements(w http.ResponseWriter, movements []*domain.Movement, err error) {
+	if err != nil {
+		writeMovementError(w, err)
+		return
+	}
+	resp := movementResponse{Movements: make([]movementJSON, len(movements))}
+	for i, m := range movements {
+		resp.Movements[i] = movementJSON{
+			MovementID:    m.MovementID.String(),
+			WarehouseID:   m.WarehouseID.String(),
+			ProductID:     m.ProductID.String(),
+			IntegrationID: optionalGuid(m.IntegrationID),
+			Delta:         m.Delta,
+			Reason:        m.Reason.String(),
+			DocumentID:    m.DocumentID.String(),
+			CreatedAt:     m.CreatedAt,
+		}
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func writeMovementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, movement.ErrInvalidQuantity),
+		errors.Is(err, movement.ErrSameWarehouse),
+		errors.Is(err, movement.ErrInvalidReason):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrNegativeRest),
+		errors.Is(err, domain.ErrInsufficientStock),
+		errors.Is(err, domain.ErrLockNotAvailable):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrReservationNotFound):
+		writeError(w, http.StatusNotFound, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,207 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/application/movement"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type movementServiceStub struct {
+	orderID   *guid.Guid
+	reason    domain.MovementReason
+	lines     []movement.Line
+	movements []*domain.Movement
+	filialID  *guid.Guid
+	at        time.Time
+	rest      *domain.Rest
+	err       error
+}
+
+func (s *movementServiceStub) Receive(_ context.Context, _, _, _ guid.Guid, lines []movement.Line) ([]*domain.Movement, error) {
+	s.reason = domain.MovementReasonRECEIPT
+	s.lines = lines
+	return s.movements, s.err
+}
+
+func (s *movementServiceStub) Ship(_ context.Context, _, _, _ guid.Guid, reason domain.MovementReason, lines []movement.Line) ([]*domain.Movement, error) {
+	s.reason = reason
+	s.lines = lines
+	return s.movements, s.err
+}
+
+func (s *movementServiceStub) ShipOrder(_ context.Context, _, orderID, _, _ guid.Guid, lines []movement.Line) ([]*domain.Movement, error) {
+	s.orderID = &orderID
+	s.reason = domain.MovementReasonSHIPMENT
+	s.lines = lines
+	return s.movements, s.err
+}
+
+func (s *movementServiceStub) Transfer(_ context.Context, _, _, _, _ guid.Guid, lines []movement.Line) ([]*domain.Movement, error) {
+	s.lines = lines
+	return s.movements, s.err
+}
+
+func (s *movementServiceStub) RestAsOf(_ context.Context, filialID *guid.Guid, _, _ guid.Guid, at time.Time) (*domain.Rest, error) {
+	s.filialID = filialID
+	s.at = at
+	return s.rest, s.err
+}
+
+func TestMovement(t *testing.T) {
+	productID := *guid.New()
+	warehouseID := *guid.New()
+	documentID := *guid.New()
+	movementID := *guid.New()
+	createdAt := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	body := `{"document_id":"` + documentID.String() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + warehouseID.String() + `",
+		"lines":[{"product_id":"` + productID.String() + `","quantity":"1.5"}]}`
+	cases := []struct {
+		Name   string
+		Path   string
+		Reason domain.MovementReason
+	}{
+		{Name: "receipt", Path: "/v1/movements/receipts", Reason: domain.MovementReasonRECEIPT},
+		{Name: "shipment", Path: "/v1/movements/shipments", Reason: domain.MovementReasonSHIPMENT},
+		{Name: "write-off", Path: "/v1/movements/write-offs", Reason: domain.MovementReasonWRITEOFF},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			stub := &movementServiceStub{movements: []*domain.Movement{{
+				MovementID:  movementID,
+				WarehouseID: warehouseID,
+				ProductID:   productID,
+				Delta:       decimal.RequireFromString("1.5"),
+				Reason:      tt.Reason,
+				DocumentID:  documentID,
+				CreatedAt:   createdAt,
+			}}}
+			mux := http.NewServeMux()
+			NewHandler(nil, nil, nil, stub, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.Path, strings.NewReader(body)))
+
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+			assert.JSONEq(t, `{"movements":[{"movement_id":"`+movementID.String()+`","warehouse_id":"`+warehouseID.String()+`",
+				"product_id":"`+productID.String()+`","integration_id":null,"delta":"1.5","reason":"`+tt.Reason.String()+`",
+				"document_id":"`+documentID.String()+`","created_at":"2024-05-01T14:00:00Z"}]}`, rec.Body.String())
+			assert.Equal(t, tt.Reason, stub.reason)
+			require.Len(t, stub.lines, 1)
+			assert.True(t, decimal.RequireFromString("1.5").Equal(stub.lines[0].Quantity))
+		})
+	}
+}
+
+func TestShipmentForOrder(t *testing.T) {
+	orderID := *guid.New()
+	stub := &movementServiceStub{movements: []*domain.Movement{}}
+	mux := http.NewServeMux()
+	NewHandler(nil, nil, nil, stub, nil).Register(mux)
+	body := `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `",
+		"order_id":"` + orderID.String() + `","lines":[{"product_id":"` + guid.NewString() + `","quantity":"1"}]}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/movements/shipments", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, &orderID, stub.orderID)
+	assert.Equal(t, domain.MovementReasonSHIPMENT, stub.reason)
+}
+
+func TestMovementErrors(t *testing.T) {
+	lines := `"lines":[{"product_id":"` + guid.NewString() + `","quantity":"1"}]`
+	movementBody := `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `",` + lines + `}`
+	orderBody := `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `","order_id":"` + guid.NewString() + `",` + lines + `}`
+	transferBody := `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","from_warehouse_id":"` + guid.NewString() + `","to_warehouse_id":"` + guid.NewString() + `",` + lines + `}`
+	cases := []struct {
+		Name   string
+		Path   string
+		Body   string
+		Err    error
+		Status int
+	}{
+		{Name: "malformed json", Path: "/v1/movements/receipts", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "bad warehouse id", Path: "/v1/movements/shipments", Body: `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"nope",` + lines + `}`, Status: http.StatusBadRequest},
+		{Name: "bad product id", Path: "/v1/movements/write-offs", Body: `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `","lines":[{"product_id":"nope","quantity":"1"}]}`, Status: http.StatusBadRequest},
+		{Name: "bad transfer source", Path: "/v1/movements/transfers", Body: `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","from_warehouse_id":"nope","to_warehouse_id":"` + guid.NewString() + `",` + lines + `}`, Status: http.StatusBadRequest},
+		{Name: "invalid quantity", Path: "/v1/movements/receipts", Body: movementBody, Err: movement.ErrInvalidQuantity, Status: http.StatusBadRequest},
+		{Name: "same warehouse", Path: "/v1/movements/transfers", Body: transferBody, Err: movement.ErrSameWarehouse, Status: http.StatusBadRequest},
+		{Name: "negative rest", Path: "/v1/movements/shipments", Body: movementBody, Err: domain.ErrNegativeRest, Status: http.StatusConflict},
+		{Name: "reserved stock", Path: "/v1/movements/write-offs", Body: movementBody, Err: domain.ErrInsufficientStock, Status: http.StatusConflict},
+		{Name: "lock not available", Path: "/v1/movements/transfers", Body: transferBody, Err: domain.ErrLockNotAvailable, Status: http.StatusConflict},
+		{Name: "bad order id", Path: "/v1/movements/shipments", Body: `{"document_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `","order_id":"nope",` + lines + `}`, Status: http.StatusBadRequest},
+		{Name: "write-off for an order", Path: "/v1/movements/write-offs", Body: orderBody, Status: http.StatusBadRequest},
+		{Name: "order without confirmed reservations", Path: "/v1/movements/shipments", Body: orderBody, Err: domain.ErrReservationNotFound, Status: http.StatusNotFound},
+		{Name: "service failure", Path: "/v1/movements/receipts", Body: movementBody, Err: errors.New("boom"), Status: http.StatusInternalServerError},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, nil, nil, &movementServiceStub{err: tt.Err}, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.Path, strings.NewReader(tt.Body)))
+			assert.Equal(t, tt.Status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestRestAsOf(t *testing.T) {
+	filialID := *guid.New()
+	warehouseID := *guid.New()
+	productID := *guid.New()
+	restID := *guid.New()
+	newQuery := func(filial string) string {
+		query := url.Values{}
+		if filial != "" {
+			query.Set("filial_id", filial)
+		}
+		query.Set("warehouse_id", warehouseID.String())
+		query.Set("product_id", productID.String())
+		query.Set("at", "2024-05-01T14:03:00Z")
+		return query.Encode()
+	}
+	rest := &domain.Rest{RestID: restID, Quantity: decimal.NewFromInt(7), WarehouseID: warehouseID, ProductID: productID}
+	cases := []struct {
+		Name      string
+		Query     string
+		Stub      *movementServiceStub
+		Status    int
+		ExpFilial *guid.Guid
+	}{
+		{Name: "with filial", Query: newQuery(filialID.String()), Stub: &movementServiceStub{rest: rest}, Status: http.StatusOK, ExpFilial: &filialID},
+		{Name: "without filial", Query: newQuery(""), Stub: &movementServiceStub{rest: rest}, Status: http.StatusOK},
+		{Name: "bad filial id", Query: newQuery("nope"), Stub: &movementServiceStub{}, Status: http.StatusBadRequest},
+		{Name: "bad moment", Query: "warehouse_id=" + warehouseID.String() + "&product_id=" + productID.String() + "&at=yesterday", Stub: &movementServiceStub{}, Status: http.StatusBadRequest},
+		{Name: "rest not found", Query: newQuery(filialID.String()), Stub: &movementServiceStub{err: domain.ErrRestNotFound}, Status: http.StatusNotFound, ExpFilial: &filialID},
+		{Name: "service failure", Query: newQuery(filialID.String()), Stub: &movementServiceStub{err: errors.New("boom")}, Status: http.StatusInternalServerError, ExpFilial: &filialID},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, nil, nil, tt.Stub, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/rests/as-of?"+tt.Query, nil))
+
+			require.Equal(t, tt.Status, rec.Code, rec.Body.String())
+			if tt.Status == http.StatusBadRequest {
+				return
+			}
+			assert.Equal(t, tt.ExpFilial, tt.Stub.filialID)
+			assert.Equal(t, time.Date(2024, 5, 1, 14, 3, 0, 0, time.UTC), tt.Stub.at)
+			if tt.Status == http.StatusOK {
+				assert.JSONEq(t, `{"rest_id":"`+restID.String()+`","filial_id":null,"integration_id":null,"quantity":"7",
+					"product_id":"`+productID.String()+`","warehouse_id":"`+warehouseID.String()+`"}`, rec.Body.String())
+			}
+		})
+	}
+}
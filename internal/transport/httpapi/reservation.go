@@ -0,0 +1,142 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/shopspring/decimal"
+)
+
+type reserveRequest struct {
+	OrderID     string           `json:"order_id"`
+	FilialID    string           `json:"filial_id"`
+	StockStates []stockStateJSON `json:"stock_states"`
+}
+
+type reserveResponse struct {
+	Reservations []reservationJSON `json:"reservations"`
+}
+
+type reservationJSON struct {
+	ReservationID string          `json:"reservation_id"`
+	OrderID       string          `json:"order_id"`
+	ProductID     string          `json:"product_id"`
+	WarehouseID   string          `json:"warehouse_id"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Status        string          `json:"status"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+}
+
+func (h *Handler) reserve(w http.ResponseWriter, r *http.Request) {
+	var req reserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	orderID, err := parseGuid("order_id", req.OrderID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filialID, err := parseGuid("filial_id", req.FilialID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	state := &models.InventoryState{StockStates: make([]*models.StockState, len(req.StockStates))}
+	for i, s := range req.StockStates {
+		stockState, err := s.toModel(fmt.Sprintf("stock_states[%d]", i))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		state.StockStates[i] = stockState
+	}
+	reservations, err := h.reservationService.Reserve(r.Context(), orderID, filialID, state)
+	if err != nil {
+		writeReservationError(w, err)
+		return
+	}
+	resp := reserveResponse{Reservations: make([]reservationJSON, len(reservations))}
+	for i, reservation := range reservations {
+		resp.Reservations[i] = reservationJSON{
+			ReservationID: reservation.ReservationID.String(),
+			OrderID:       reservation.OrderID.String(),
+			ProductID:     reservation.ProductID.String(),
+			WarehouseID:   reservation.WarehouseID.String(),
+			Quantity:      reservation.Quantity,
+			Status:        reservation.Status.String(),
+			ExpiresAt:     reservation.ExpiresAt,
+		}
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) confirmReservation(w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseGuid("order_id", r.PathValue("order_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err = h.reservationService.Confirm(r.Context(), orderID); err != nil {
+		writeReservationError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) releaseReservation(w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseGuid("order_id", r.PathValue("order_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err = h.reservationService.Release(r.Context(), orderID); err != nil {
+		writeReservationError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeReservationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock),
+		errors.Is(err, domain.ErrAlreadyReserved),
+		errors.Is(err, domain.ErrLockNotAvailable):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrReservationNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrReservationExpired):
+		writeError(w, http.StatusGone, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func (s *stockStateJSON) toModel(field string) (*models.StockState, error) {
+	productID, err := parseGuid(field+".product_id", s.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%s.quantity: must be positive", field)
+	}
+	stockState := &models.StockState{ProductID: productID, Quantity: s.Quantity, Produce: s.Produce}
+	if s.Produce {
+		return stockState, nil
+	}
+	if s.WarehouseID == nil {
+		return nil, fmt.Errorf("%s.warehouse_id: must be set unless produce", field)
+	}
+	warehouseID, err := parseGuid(field+".warehouse_id", *s.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	stockState.WarehouseID = &warehouseID
+	return stockState, nil
+}
@@ -0,0 +1,132 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/DimKa163/stocks/internal/domain/models"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reservationServiceStub struct {
+	state        *models.InventoryState
+	reservations []*domain.Reservation
+	err          error
+}
+
+func (s *reservationServiceStub) Reserve(_ context.Context, _, _ guid.Guid, state *models.InventoryState) ([]*domain.Reservation, error) {
+	s.state = state
+	return s.reservations, s.err
+}
+
+func (s *reservationServiceStub) Confirm(_ context.Context, _ guid.Guid) error {
+	return s.err
+}
+
+func (s *reservationServiceStub) Release(_ context.Context, _ guid.Guid) error {
+	return s.err
+}
+
+func TestReserve(t *testing.T) {
+	orderID := *guid.New()
+	filialID := *guid.New()
+	productID := *guid.New()
+	warehouseID := *guid.New()
+	reservationID := *guid.New()
+	expiresAt := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	stub := &reservationServiceStub{reservations: []*domain.Reservation{{
+		ReservationID: reservationID,
+		OrderID:       orderID,
+		FilialID:      filialID,
+		ProductID:     productID,
+		WarehouseID:   warehouseID,
+		Quantity:      decimal.NewFromInt(2),
+		Status:        domain.ReservationStatusACTIVE,
+		ExpiresAt:     expiresAt,
+	}}}
+	mux := http.NewServeMux()
+	NewHandler(nil, nil, stub, nil, nil).Register(mux)
+
+	body := `{"order_id":"` + orderID.String() + `","filial_id":"` + filialID.String() + `","stock_states":[
+		{"product_id":"` + productID.String() + `","quantity":"2","warehouse_id":"` + warehouseID.String() + `"},
+		{"product_id":"` + productID.String() + `","quantity":"1","produce":true}
+	]}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/reservations", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"reservations":[{"reservation_id":"`+reservationID.String()+`","order_id":"`+orderID.String()+`",
+		"product_id":"`+productID.String()+`","warehouse_id":"`+warehouseID.String()+`","quantity":"2",
+		"status":"ACTIVE","expires_at":"2024-05-01T14:00:00Z"}]}`, rec.Body.String())
+	require.Len(t, stub.state.StockStates, 2)
+	assert.Equal(t, &warehouseID, stub.state.StockStates[0].WarehouseID)
+	assert.True(t, stub.state.StockStates[1].Produce)
+}
+
+func TestReserveErrors(t *testing.T) {
+	stockState := `{"product_id":"` + guid.NewString() + `","quantity":"1","warehouse_id":"` + guid.NewString() + `"}`
+	newBody := func(stockStates string) string {
+		return `{"order_id":"` + guid.NewString() + `","filial_id":"` + guid.NewString() + `","stock_states":[` + stockStates + `]}`
+	}
+	cases := []struct {
+		Name   string
+		Body   string
+		Err    error
+		Status int
+	}{
+		{Name: "malformed json", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "bad order id", Body: `{"order_id":"nope","filial_id":"` + guid.NewString() + `","stock_states":[]}`, Status: http.StatusBadRequest},
+		{Name: "non positive quantity", Body: newBody(`{"product_id":"` + guid.NewString() + `","quantity":"0","warehouse_id":"` + guid.NewString() + `"}`), Status: http.StatusBadRequest},
+		{Name: "missing warehouse", Body: newBody(`{"product_id":"` + guid.NewString() + `","quantity":"1"}`), Status: http.StatusBadRequest},
+		{Name: "insufficient stock", Body: newBody(stockState), Err: domain.ErrInsufficientStock, Status: http.StatusConflict},
+		{Name: "already reserved", Body: newBody(stockState), Err: domain.ErrAlreadyReserved, Status: http.StatusConflict},
+		{Name: "lock not available", Body: newBody(stockState), Err: fmt.Errorf("reserve: %w", domain.ErrLockNotAvailable), Status: http.StatusConflict},
+		{Name: "service failure", Body: newBody(stockState), Err: errors.New("boom"), Status: http.StatusInternalServerError},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, nil, &reservationServiceStub{err: tt.Err}, nil, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/reservations", strings.NewReader(tt.Body)))
+			assert.Equal(t, tt.Status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestReservationTransitions(t *testing.T) {
+	cases := []struct {
+		Name    string
+		OrderID string
+		Err     error
+		Status  int
+	}{
+		{Name: "done", OrderID: guid.NewString(), Status: http.StatusNoContent},
+		{Name: "bad order id", OrderID: "nope", Status: http.StatusBadRequest},
+		{Name: "not found", OrderID: guid.NewString(), Err: domain.ErrReservationNotFound, Status: http.StatusNotFound},
+		{Name: "expired", OrderID: guid.NewString(), Err: domain.ErrReservationExpired, Status: http.StatusGone},
+		{Name: "lock not available", OrderID: guid.NewString(), Err: domain.ErrLockNotAvailable, Status: http.StatusConflict},
+		{Name: "service failure", OrderID: guid.NewString(), Err: errors.New("boom"), Status: http.StatusInternalServerError},
+	}
+	for _, action := range []string{"confirm", "release"} {
+		for _, tt := range cases {
+			t.Run(action+" "+tt.Name, func(t *testing.T) {
+				mux := http.NewServeMux()
+				NewHandler(nil, nil, &reservationServiceStub{err: tt.Err}, nil, nil).Register(mux)
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/reservations/"+tt.OrderID+"/"+action, nil))
+				assert.Equal(t, tt.Status, rec.Code, rec.Body.String())
+			})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DimKa163/stocks/internal/application/ingest"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+type snapshotRequest struct {
+	DocumentID string             `json:"document_id"`
+	Mode       string             `json:"mode"`
+	FilialID   *string            `json:"filial_id"`
+	Rests      []snapshotRestJSON `json:"rests"`
+}
+
+type snapshotRestJSON struct {
+	IntegrationID string          `json:"integration_id"`
+	FilialID      *string         `json:"filial_id"`
+	WarehouseID   string          `json:"warehouse_id"`
+	ProductID     string          `json:"product_id"`
+	Quantity      decimal.Decimal `json:"quantity"`
+}
+
+type snapshotResponse struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+func (h *Handler) ingestSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req snapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	snapshot, err := req.toModel()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stats, err := h.ingestService.Ingest(r.Context(), snapshot)
+	if err != nil {
+		switch {
+		case errors.Is(err, ingest.ErrNegativeQuantity),
+			errors.Is(err, domain.ErrDuplicateIntegrationID):
+			writeError(w, http.StatusBadRequest, err)
+		case errors.Is(err, domain.ErrNegativeRest),
+			errors.Is(err, domain.ErrIntegrationKeyMismatch):
+			writeError(w, http.StatusConflict, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshotResponse{
+		Inserted:  stats.Inserted,
+		Updated:   stats.Updated,
+		Unchanged: stats.Unchanged,
+		Removed:   stats.Removed,
+	})
+}
+
+func (s *snapshotRequest) toModel() (*domain.RestSnapshot, error) {
+	documentID, err := parseGuid("document_id", s.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &domain.RestSnapshot{DocumentID: documentID, Rests: make([]*domain.SnapshotRest, len(s.Rests))}
+	switch s.Mode {
+	case "full":
+		snapshot.Full = true
+	case "", "delta":
+	default:
+		return nil, fmt.Errorf("mode: unknown mode %q", s.Mode)
+	}
+	if s.FilialID != nil {
+		filialID, err := parseGuid("filial_id", *s.FilialID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.FilialID = &filialID
+	}
+	for i, rest := range s.Rests {
+		field := fmt.Sprintf("rests[%d]", i)
+		integrationID, err := parseGuid(field+".integration_id", rest.IntegrationID)
+		if err != nil {
+			return nil, err
+		}
+		warehouseID, err := parseGuid(field+".warehouse_id", rest.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		productID, err := parseGuid(field+".product_id", rest.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		snapshotRest := &domain.SnapshotRest{
+			IntegrationID: integrationID,
+			WarehouseID:   warehouseID,
+			ProductID:     productID,
+			Quantity:      rest.Quantity,
+		}
+		if rest.FilialID != nil {
+			filialID, err := parseGuid(field+".filial_id", *rest.FilialID)
+			if err != nil {
+				return nil, err
+			}
+			snapshotRest.FilialID = &filialID
+		}
+		snapshot.Rests[i] = snapshotRest
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/application/ingest"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ingestServiceStub struct {
+	snapshot *domain.RestSnapshot
+	stats    *ingest.Stats
+	err      error
+}
+
+func (s *ingestServiceStub) Ingest(_ context.Context, snapshot *domain.RestSnapshot) (*ingest.Stats, error) {
+	s.snapshot = snapshot
+	return s.stats, s.err
+}
+
+func (s *ingestServiceStub) Import(_ context.Context, _ guid.Guid, _ func() (domain.SnapshotRestSource, error), _ bool) (*ingest.ImportResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestIngestSnapshot(t *testing.T) {
+	filialID := *guid.New()
+	integrationID := *guid.New()
+	stub := &ingestServiceStub{stats: &ingest.Stats{Inserted: 1, Updated: 2, Unchanged: 3, Removed: 4}}
+	mux := http.NewServeMux()
+	NewHandler(nil, nil, nil, nil, stub).Register(mux)
+
+	body := `{"document_id":"` + guid.NewString() + `","mode":"full","filial_id":"` + filialID.String() + `","rests":[
+		{"integration_id":"` + integrationID.String() + `","filial_id":"` + filialID.String() + `","warehouse_id":"` + guid.NewString() + `","product_id":"` + guid.NewString() + `","quantity":"2.5"}
+	]}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/rest-snapshots", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"inserted":1,"updated":2,"unchanged":3,"removed":4}`, rec.Body.String())
+	assert.True(t, stub.snapshot.Full)
+	assert.Equal(t, &filialID, stub.snapshot.FilialID)
+	require.Len(t, stub.snapshot.Rests, 1)
+	assert.Equal(t, integrationID, stub.snapshot.Rests[0].IntegrationID)
+	assert.Equal(t, &filialID, stub.snapshot.Rests[0].FilialID)
+	assert.Equal(t, "2.5", stub.snapshot.Rests[0].Quantity.String())
+}
+
+func TestIngestSnapshotErrors(t *testing.T) {
+	rest := `{"integration_id":"` + guid.NewString() + `","warehouse_id":"` + guid.NewString() + `","product_id":"` + guid.NewString() + `","quantity":"1"}`
+	body := `{"document_id":"` + guid.NewString() + `","rests":[` + rest + `]}`
+	cases := []struct {
+		Name   string
+		Body   string
+		Err    error
+		Status int
+	}{
+		{Name: "malformed json", Body: `{`, Status: http.StatusBadRequest},
+		{Name: "unknown mode", Body: `{"document_id":"` + guid.NewString() + `","mode":"partial","rests":[` + rest + `]}`, Status: http.StatusBadRequest},
+		{Name: "bad integration id", Body: `{"document_id":"` + guid.NewString() + `","rests":[{"integration_id":"nope","warehouse_id":"` + guid.NewString() + `","product_id":"` + guid.NewString() + `","quantity":"1"}]}`, Status: http.StatusBadRequest},
+		{Name: "negative quantity", Body: body, Err: fmt.Errorf("rests[0]: %w", ingest.ErrNegativeQuantity), Status: http.StatusBadRequest},
+		{Name: "duplicate integration id", Body: body, Err: domain.ErrDuplicateIntegrationID, Status: http.StatusBadRequest},
+		{Name: "negative rest", Body: body, Err: domain.ErrNegativeRest, Status: http.StatusConflict},
+		{Name: "integration key mismatch", Body: body, Err: domain.ErrIntegrationKeyMismatch, Status: http.StatusConflict},
+		{Name: "service failure", Body: body, Err: errors.New("boom"), Status: http.StatusInternalServerError},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, nil, nil, nil, &ingestServiceStub{err: tt.Err}).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/rest-snapshots", strings.NewReader(tt.Body)))
+			assert.Equal(t, tt.Status, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+)
+
+type stockInfoRequest struct {
+	FilialID   string               `json:"filial_id"`
+	ShipmentID string               `json:"shipment_id"`
+	Product    requestedProductJSON `json:"product"`
+}
+
+type stockInfoBatchRequest struct {
+	FilialID   string                 `json:"filial_id"`
+	ShipmentID string                 `json:"shipment_id"`
+	Products   []requestedProductJSON `json:"products"`
+}
+
+type requestedProductJSON struct {
+	ProductID string          `json:"product_id"`
+	Quantity  decimal.Decimal `json:"quantity"`
+}
+
+type stockInfoResponse struct {
+	InStock bool            `json:"in_stock"`
+	Product productInfoJSON `json:"product"`
+}
+
+type stockInfoBatchResponse struct {
+	InStock  bool              `json:"in_stock"`
+	Products []productInfoJSON `json:"products"`
+}
+
+type productInfoJSON struct {
+	ProductID string    `json:"product_id"`
+	Covered   bool      `json:"covered"`
+	Rest      *restJSON `json:"rest"`
+}
+
+type restJSON struct {
+	RestID        string          `json:"rest_id"`
+	FilialID      *string         `json:"filial_id"`
+	IntegrationID *string         `json:"integration_id"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	ProductID     string          `json:"product_id"`
+	WarehouseID   string          `json:"warehouse_id"`
+}
+
+func (h *Handler) stockInfo(w http.ResponseWriter, r *http.Request) {
+	var req stockInfoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filialID, shipmentID, err := parseStockLocation(req.FilialID, req.ShipmentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	product, err := req.Product.toModel("product")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stockInfo, err := h.restInfoService.GetStockOneItemInfo(r.Context(), product, filialID, shipmentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stockInfoResponse{
+		InStock: stockInfo.InStock,
+		Product: newProductInfoJSON(stockInfo.ProductInfo),
+	})
+}
+
+func (h *Handler) stockInfoBatch(w http.ResponseWriter, r *http.Request) {
+	var req stockInfoBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filialID, shipmentID, err := parseStockLocation(req.FilialID, req.ShipmentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Products) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("products: must not be empty"))
+		return
+	}
+	products := make([]info.RequestedProduct, len(req.Products))
+	for i, p := range req.Products {
+		product, err := p.toModel(fmt.Sprintf("products[%d]", i))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		products[i] = product
+	}
+	stockInfo, err := h.restInfoService.GetStockManyItemsInfo(r.Context(), products, filialID, shipmentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := stockInfoBatchResponse{
+		InStock:  stockInfo.InStock,
+		Products: make([]productInfoJSON, len(stockInfo.ProductInfo)),
+	}
+	for i, p := range stockInfo.ProductInfo {
+		resp.Products[i] = newProductInfoJSON(p)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func parseStockLocation(filial, shipment string) (guid.Guid, guid.Guid, error) {
+	filialID, err := parseGuid("filial_id", filial)
+	if err != nil {
+		return guid.Guid{}, guid.Guid{}, err
+	}
+	shipmentID, err := parseGuid("shipment_id", shipment)
+	if err != nil {
+		return guid.Guid{}, guid.Guid{}, err
+	}
+	return filialID, shipmentID, nil
+}
+
+func (p *requestedProductJSON) toModel(field string) (info.RequestedProduct, error) {
+	productID, err := parseGuid(field+".product_id", p.ProductID)
+	if err != nil {
+		return info.RequestedProduct{}, err
+	}
+	if !p.Quantity.IsPositive() {
+		return info.RequestedProduct{}, fmt.Errorf("%s.quantity: must be positive", field)
+	}
+	return info.RequestedProduct{ProductID: productID, Quantity: p.Quantity}, nil
+}
+
+func newProductInfoJSON(p info.ProductInfo) productInfoJSON {
+	return productInfoJSON{
+		ProductID: p.ProductID.String(),
+		Covered:   p.Covered,
+		Rest:      newRestJSON(p.Rest),
+	}
+}
+
+func newRestJSON(rest *domain.Rest) *restJSON {
+	if rest == nil {
+		return nil
+	}
+	return &restJSON{
+		RestID:        rest.RestID.String(),
+		FilialID:      optionalGuid(rest.FilialID),
+		IntegrationID: optionalGuid(rest.IntegrationID),
+		Quantity:      rest.Quantity,
+		ProductID:     rest.ProductID.String(),
+		WarehouseID:   rest.WarehouseID.String(),
+	}
+}
+
+func optionalGuid(g *guid.Guid) *string {
+	if g == nil {
+		return nil
+	}
+	s := g.String()
+	return &s
+}
@@ -0,0 +1,156 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DimKa163/stocks/internal/application/info"
+	"github.com/DimKa163/stocks/internal/domain"
+	"github.com/beevik/guid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type restInfoServiceStub struct {
+	one  *info.OneStockInfo
+	many *info.ManyStockInfo
+	err  error
+}
+
+func (s *restInfoServiceStub) GetStockOneItemInfo(_ context.Context, _ info.RequestedProduct, _, _ guid.Guid) (*info.OneStockInfo, error) {
+	return s.one, s.err
+}
+
+func (s *restInfoServiceStub) GetStockManyItemsInfo(_ context.Context, _ []info.RequestedProduct, _, _ guid.Guid) (*info.ManyStockInfo, error) {
+	return s.many, s.err
+}
+
+func TestStockInfo(t *testing.T) {
+	productID := *guid.New()
+	warehouseID := *guid.New()
+	restID := *guid.New()
+	body := `{"filial_id":"` + guid.NewString() + `","shipment_id":"` + guid.NewString() + `","product":{"product_id":"` + productID.String() + `","quantity":"2"}}`
+	cases := []struct {
+		Name   string
+		Stub   *restInfoServiceStub
+		Body   string
+		Status int
+		Exp    string
+	}{
+		{
+			Name: "rest in stock",
+			Stub: &restInfoServiceStub{one: &info.OneStockInfo{InStock: true, ProductInfo: info.ProductInfo{
+				ProductID: productID,
+				Covered:   true,
+				Rest:      &domain.Rest{RestID: restID, Quantity: decimal.NewFromInt(5), ProductID: productID, WarehouseID: warehouseID},
+			}}},
+			Body:   body,
+			Status: http.StatusOK,
+			Exp: `{"in_stock":true,"product":{"product_id":"` + productID.String() + `","covered":true,"rest":{
+				"rest_id":"` + restID.String() + `","filial_id":null,"integration_id":null,"quantity":"5",
+				"product_id":"` + productID.String() + `","warehouse_id":"` + warehouseID.String() + `"}}}`,
+		},
+		{
+			Name:   "rest not found is reported as zero stock",
+			Stub:   &restInfoServiceStub{one: &info.OneStockInfo{ProductInfo: info.ProductInfo{ProductID: productID}}},
+			Body:   body,
+			Status: http.StatusOK,
+			Exp:    `{"in_stock":false,"product":{"product_id":"` + productID.String() + `","covered":false,"rest":null}}`,
+		},
+		{
+			Name:   "malformed json",
+			Stub:   &restInfoServiceStub{},
+			Body:   `{`,
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "bad shipment id",
+			Stub:   &restInfoServiceStub{},
+			Body:   `{"filial_id":"` + guid.NewString() + `","shipment_id":"nope","product":{"product_id":"` + productID.String() + `","quantity":"2"}}`,
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "non positive quantity",
+			Stub:   &restInfoServiceStub{},
+			Body:   `{"filial_id":"` + guid.NewString() + `","shipment_id":"` + guid.NewString() + `","product":{"product_id":"` + productID.String() + `","quantity":"0"}}`,
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "service failure",
+			Stub:   &restInfoServiceStub{err: errors.New("boom")},
+			Body:   body,
+			Status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, tt.Stub, nil, nil, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/stock-info", strings.NewReader(tt.Body)))
+
+			require.Equal(t, tt.Status, rec.Code, rec.Body.String())
+			if tt.Exp != "" {
+				assert.JSONEq(t, tt.Exp, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStockInfoBatch(t *testing.T) {
+	productID := *guid.New()
+	location := `"filial_id":"` + guid.NewString() + `","shipment_id":"` + guid.NewString() + `"`
+	cases := []struct {
+		Name   string
+		Stub   *restInfoServiceStub
+		Body   string
+		Status int
+		Exp    string
+	}{
+		{
+			Name: "rest not found is reported as zero stock",
+			Stub: &restInfoServiceStub{many: &info.ManyStockInfo{ProductInfo: []info.ProductInfo{
+				{ProductID: productID},
+			}}},
+			Body:   `{` + location + `,"products":[{"product_id":"` + productID.String() + `","quantity":"1"}]}`,
+			Status: http.StatusOK,
+			Exp:    `{"in_stock":false,"products":[{"product_id":"` + productID.String() + `","covered":false,"rest":null}]}`,
+		},
+		{
+			Name:   "empty products",
+			Stub:   &restInfoServiceStub{},
+			Body:   `{` + location + `,"products":[]}`,
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "bad product id",
+			Stub:   &restInfoServiceStub{},
+			Body:   `{` + location + `,"products":[{"product_id":"nope","quantity":"1"}]}`,
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "service failure",
+			Stub:   &restInfoServiceStub{err: errors.New("boom")},
+			Body:   `{` + location + `,"products":[{"product_id":"` + productID.String() + `","quantity":"1"}]}`,
+			Status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(nil, tt.Stub, nil, nil, nil).Register(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/stock-info/batch", strings.NewReader(tt.Body)))
+
+			require.Equal(t, tt.Status, rec.Code, rec.Body.String())
+			if tt.Exp != "" {
+				assert.JSONEq(t, tt.Exp, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/movement.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/DimKa163/stocks/internal/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockLedgerRepository) Append(ctx context.Context, movement *domain.Movement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, movement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockLedgerRepositoryMockRecorder) Append(ctx, movement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockLedgerRepository)(nil).Append), ctx, movement)
+}
+
+// RestAsOf mocks base method.
+func (m *MockLedgerRepository) RestAsOf(ctx context.Context, filialID *guid.Guid, warehouseID, productID guid.Guid, at time.Time) (*domain.Rest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestAsOf", ctx, filialID, warehouseID, productID, at)
+	ret0, _ := ret[0].(*domain.Rest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestAsOf indicates an expected call of RestAsOf.
+func (mr *MockLedgerRepositoryMockRecorder) RestAsOf(ctx, filialID, warehouseID, productID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestAsOf", reflect.TypeOf((*MockLedgerRepository)(nil).RestAsOf), ctx, filialID, warehouseID, productID, at)
+}
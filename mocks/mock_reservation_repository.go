@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/reservation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/DimKa163/stocks/internal/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReservationRepository is a mock of ReservationRepository interface.
+type MockReservationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationRepositoryMockRecorder
+}
+
+// MockReservationRepositoryMockRecorder is the mock recorder for MockReservationRepository.
+type MockReservationRepositoryMockRecorder struct {
+	mock *MockReservationRepository
+}
+
+// NewMockReservationRepository creates a new mock instance.
+func NewMockReservationRepository(ctrl *gomock.Controller) *MockReservationRepository {
+	mock := &MockReservationRepository{ctrl: ctrl}
+	mock.recorder = &MockReservationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationRepository) EXPECT() *MockReservationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpireBatch mocks base method.
+func (m *MockReservationRepository) ExpireBatch(ctx context.Context, limit int) ([]*domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBatch", ctx, limit)
+	ret0, _ := ret[0].([]*domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireBatch indicates an expected call of ExpireBatch.
+func (mr *MockReservationRepositoryMockRecorder) ExpireBatch(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBatch", reflect.TypeOf((*MockReservationRepository)(nil).ExpireBatch), ctx, limit)
+}
+
+// Fulfil mocks base method.
+func (m *MockReservationRepository) Fulfil(ctx context.Context, orderID, warehouseID guid.Guid) ([]*domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fulfil", ctx, orderID, warehouseID)
+	ret0, _ := ret[0].([]*domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fulfil indicates an expected call of Fulfil.
+func (mr *MockReservationRepositoryMockRecorder) Fulfil(ctx, orderID, warehouseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fulfil", reflect.TypeOf((*MockReservationRepository)(nil).Fulfil), ctx, orderID, warehouseID)
+}
+
+// GetByOrder mocks base method.
+func (m *MockReservationRepository) GetByOrder(ctx context.Context, orderID guid.Guid) ([]*domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrder", ctx, orderID)
+	ret0, _ := ret[0].([]*domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrder indicates an expected call of GetByOrder.
+func (mr *MockReservationRepositoryMockRecorder) GetByOrder(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrder", reflect.TypeOf((*MockReservationRepository)(nil).GetByOrder), ctx, orderID)
+}
+
+// Hold mocks base method.
+func (m *MockReservationRepository) Hold(ctx context.Context, reservation *domain.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockReservationRepositoryMockRecorder) Hold(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockReservationRepository)(nil).Hold), ctx, reservation)
+}
+
+// LockOrder mocks base method.
+func (m *MockReservationRepository) LockOrder(ctx context.Context, orderID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockOrder indicates an expected call of LockOrder.
+func (mr *MockReservationRepositoryMockRecorder) LockOrder(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOrder", reflect.TypeOf((*MockReservationRepository)(nil).LockOrder), ctx, orderID)
+}
+
+// SetStatus mocks base method.
+func (m *MockReservationRepository) SetStatus(ctx context.Context, orderID guid.Guid, from, to domain.ReservationStatus) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, orderID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockReservationRepositoryMockRecorder) SetStatus(ctx, orderID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockReservationRepository)(nil).SetStatus), ctx, orderID, from, to)
+}
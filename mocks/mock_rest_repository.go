@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: I:\GoLand\stocks\internal\domain\rest.go
+// Source: internal/domain/rest.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -11,6 +11,7 @@ import (
 	domain "github.com/DimKa163/stocks/internal/domain"
 	guid "github.com/beevik/guid"
 	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
 )
 
 // MockRestRepository is a mock of RestRepository interface.
@@ -36,6 +37,35 @@ func (m *MockRestRepository) EXPECT() *MockRestRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Adjust mocks base method.
+func (m *MockRestRepository) Adjust(ctx context.Context, filialID, warehouseID, productID guid.Guid, delta decimal.Decimal) (*domain.Rest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, filialID, warehouseID, productID, delta)
+	ret0, _ := ret[0].(*domain.Rest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockRestRepositoryMockRecorder) Adjust(ctx, filialID, warehouseID, productID, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockRestRepository)(nil).Adjust), ctx, filialID, warehouseID, productID, delta)
+}
+
+// Each mocks base method.
+func (m *MockRestRepository) Each(ctx context.Context, filter domain.RestFilter, fn func(*domain.Rest) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Each", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Each indicates an expected call of Each.
+func (mr *MockRestRepositoryMockRecorder) Each(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Each", reflect.TypeOf((*MockRestRepository)(nil).Each), ctx, filter, fn)
+}
+
 // Get mocks base method.
 func (m *MockRestRepository) Get(ctx context.Context, filialID, warehouseID, productID guid.Guid) (*domain.Rest, error) {
 	m.ctrl.T.Helper()
@@ -50,3 +80,77 @@ func (mr *MockRestRepositoryMockRecorder) Get(ctx, filialID, warehouseID, produc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRestRepository)(nil).Get), ctx, filialID, warehouseID, productID)
 }
+
+// GetMany mocks base method.
+func (m *MockRestRepository) GetMany(ctx context.Context, filialID guid.Guid, warehouseIDs, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, filialID, warehouseIDs, productIDs)
+	ret0, _ := ret[0].([]*domain.Rest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockRestRepositoryMockRecorder) GetMany(ctx, filialID, warehouseIDs, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockRestRepository)(nil).GetMany), ctx, filialID, warehouseIDs, productIDs)
+}
+
+// GetPairs mocks base method.
+func (m *MockRestRepository) GetPairs(ctx context.Context, filialID guid.Guid, warehouseIDs, productIDs []guid.Guid) ([]*domain.Rest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairs", ctx, filialID, warehouseIDs, productIDs)
+	ret0, _ := ret[0].([]*domain.Rest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairs indicates an expected call of GetPairs.
+func (mr *MockRestRepositoryMockRecorder) GetPairs(ctx, filialID, warehouseIDs, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairs", reflect.TypeOf((*MockRestRepository)(nil).GetPairs), ctx, filialID, warehouseIDs, productIDs)
+}
+
+// Sync mocks base method.
+func (m *MockRestRepository) Sync(ctx context.Context, rest *domain.SnapshotRest) (*domain.SyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, rest)
+	ret0, _ := ret[0].(*domain.SyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockRestRepositoryMockRecorder) Sync(ctx, rest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockRestRepository)(nil).Sync), ctx, rest)
+}
+
+// WithLock mocks base method.
+func (m *MockRestRepository) WithLock(lock domain.RowLock) domain.RestRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLock", lock)
+	ret0, _ := ret[0].(domain.RestRepository)
+	return ret0
+}
+
+// WithLock indicates an expected call of WithLock.
+func (mr *MockRestRepositoryMockRecorder) WithLock(lock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLock", reflect.TypeOf((*MockRestRepository)(nil).WithLock), lock)
+}
+
+// ZeroMissing mocks base method.
+func (m *MockRestRepository) ZeroMissing(ctx context.Context, filialID *guid.Guid, keep []guid.Guid) ([]*domain.SyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZeroMissing", ctx, filialID, keep)
+	ret0, _ := ret[0].([]*domain.SyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZeroMissing indicates an expected call of ZeroMissing.
+func (mr *MockRestRepositoryMockRecorder) ZeroMissing(ctx, filialID, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZeroMissing", reflect.TypeOf((*MockRestRepository)(nil).ZeroMissing), ctx, filialID, keep)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/snapshot.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/DimKa163/stocks/internal/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRestStagingRepository is a mock of RestStagingRepository interface.
+type MockRestStagingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestStagingRepositoryMockRecorder
+}
+
+// MockRestStagingRepositoryMockRecorder is the mock recorder for MockRestStagingRepository.
+type MockRestStagingRepositoryMockRecorder struct {
+	mock *MockRestStagingRepository
+}
+
+// NewMockRestStagingRepository creates a new mock instance.
+func NewMockRestStagingRepository(ctrl *gomock.Controller) *MockRestStagingRepository {
+	mock := &MockRestStagingRepository{ctrl: ctrl}
+	mock.recorder = &MockRestStagingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestStagingRepository) EXPECT() *MockRestStagingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Diff mocks base method.
+func (m *MockRestStagingRepository) Diff(ctx context.Context) ([]*domain.RestDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx)
+	ret0, _ := ret[0].([]*domain.RestDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockRestStagingRepositoryMockRecorder) Diff(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockRestStagingRepository)(nil).Diff), ctx)
+}
+
+// Merge mocks base method.
+func (m *MockRestStagingRepository) Merge(ctx context.Context, documentID guid.Guid, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, documentID, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockRestStagingRepositoryMockRecorder) Merge(ctx, documentID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockRestStagingRepository)(nil).Merge), ctx, documentID, at)
+}
+
+// Stage mocks base method.
+func (m *MockRestStagingRepository) Stage(ctx context.Context, next domain.SnapshotRestSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stage", ctx, next)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stage indicates an expected call of Stage.
+func (mr *MockRestStagingRepositoryMockRecorder) Stage(ctx, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stage", reflect.TypeOf((*MockRestStagingRepository)(nil).Stage), ctx, next)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/uow.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/DimKa163/stocks/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockUnitOfWork) Begin(ctx context.Context, fn func(domain.UnitOfWork) error, opts ...domain.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Begin", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockUnitOfWorkMockRecorder) Begin(ctx, fn interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockUnitOfWork)(nil).Begin), varargs...)
+}
+
+// Ledger mocks base method.
+func (m *MockUnitOfWork) Ledger() domain.LedgerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ledger")
+	ret0, _ := ret[0].(domain.LedgerRepository)
+	return ret0
+}
+
+// Ledger indicates an expected call of Ledger.
+func (mr *MockUnitOfWorkMockRecorder) Ledger() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ledger", reflect.TypeOf((*MockUnitOfWork)(nil).Ledger))
+}
+
+// Outbox mocks base method.
+func (m *MockUnitOfWork) Outbox() domain.OutboxRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Outbox")
+	ret0, _ := ret[0].(domain.OutboxRepository)
+	return ret0
+}
+
+// Outbox indicates an expected call of Outbox.
+func (mr *MockUnitOfWorkMockRecorder) Outbox() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Outbox", reflect.TypeOf((*MockUnitOfWork)(nil).Outbox))
+}
+
+// Reservation mocks base method.
+func (m *MockUnitOfWork) Reservation() domain.ReservationRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reservation")
+	ret0, _ := ret[0].(domain.ReservationRepository)
+	return ret0
+}
+
+// Reservation indicates an expected call of Reservation.
+func (mr *MockUnitOfWorkMockRecorder) Reservation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reservation", reflect.TypeOf((*MockUnitOfWork)(nil).Reservation))
+}
+
+// Rest mocks base method.
+func (m *MockUnitOfWork) Rest() domain.RestRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rest")
+	ret0, _ := ret[0].(domain.RestRepository)
+	return ret0
+}
+
+// Rest indicates an expected call of Rest.
+func (mr *MockUnitOfWorkMockRecorder) Rest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rest", reflect.TypeOf((*MockUnitOfWork)(nil).Rest))
+}
+
+// Staging mocks base method.
+func (m *MockUnitOfWork) Staging() domain.RestStagingRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Staging")
+	ret0, _ := ret[0].(domain.RestStagingRepository)
+	return ret0
+}
+
+// Staging indicates an expected call of Staging.
+func (mr *MockUnitOfWorkMockRecorder) Staging() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Staging", reflect.TypeOf((*MockUnitOfWork)(nil).Staging))
+}
+
+// Warehouse mocks base method.
+func (m *MockUnitOfWork) Warehouse() domain.WarehouseRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Warehouse")
+	ret0, _ := ret[0].(domain.WarehouseRepository)
+	return ret0
+}
+
+// Warehouse indicates an expected call of Warehouse.
+func (mr *MockUnitOfWorkMockRecorder) Warehouse() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warehouse", reflect.TypeOf((*MockUnitOfWork)(nil).Warehouse))
+}
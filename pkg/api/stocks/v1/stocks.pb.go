@@ -0,0 +1,1437 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: stocks/v1/stocks.proto
+
+package stocksv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChoicePriority int32
+
+const (
+	ChoicePriority_CHOICE_PRIORITY_UNSPECIFIED ChoicePriority = 0
+	ChoicePriority_CHOICE_PRIORITY_NEAREST     ChoicePriority = 1
+	ChoicePriority_CHOICE_PRIORITY_FARTHEST    ChoicePriority = 2
+	// Splits the whole order over as few warehouses as possible.
+	ChoicePriority_CHOICE_PRIORITY_FEWEST_WAREHOUSES ChoicePriority = 3
+	// Takes stock from the nodes with the lowest unit cost meeting the deadline.
+	ChoicePriority_CHOICE_PRIORITY_CHEAPEST ChoicePriority = 4
+)
+
+// Enum value maps for ChoicePriority.
+var (
+	ChoicePriority_name = map[int32]string{
+		0: "CHOICE_PRIORITY_UNSPECIFIED",
+		1: "CHOICE_PRIORITY_NEAREST",
+		2: "CHOICE_PRIORITY_FARTHEST",
+		3: "CHOICE_PRIORITY_FEWEST_WAREHOUSES",
+		4: "CHOICE_PRIORITY_CHEAPEST",
+	}
+	ChoicePriority_value = map[string]int32{
+		"CHOICE_PRIORITY_UNSPECIFIED":       0,
+		"CHOICE_PRIORITY_NEAREST":           1,
+		"CHOICE_PRIORITY_FARTHEST":          2,
+		"CHOICE_PRIORITY_FEWEST_WAREHOUSES": 3,
+		"CHOICE_PRIORITY_CHEAPEST":          4,
+	}
+)
+
+func (x ChoicePriority) Enum() *ChoicePriority {
+	p := new(ChoicePriority)
+	*p = x
+	return p
+}
+
+func (x ChoicePriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChoicePriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_stocks_v1_stocks_proto_enumTypes[0].Descriptor()
+}
+
+func (ChoicePriority) Type() protoreflect.EnumType {
+	return &file_stocks_v1_stocks_proto_enumTypes[0]
+}
+
+func (x ChoicePriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChoicePriority.Descriptor instead.
+func (ChoicePriority) EnumDescriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{0}
+}
+
+type InventoryResult int32
+
+const (
+	InventoryResult_INVENTORY_RESULT_UNSPECIFIED             InventoryResult = 0
+	InventoryResult_INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE     InventoryResult = 1
+	InventoryResult_INVENTORY_RESULT_ALL_IN_STOCK_AT_SEVERAL InventoryResult = 2
+	InventoryResult_INVENTORY_RESULT_PARTIALLY_IN_STOCK      InventoryResult = 3
+	InventoryResult_INVENTORY_RESULT_ALL_TO_PRODUCE          InventoryResult = 4
+)
+
+// Enum value maps for InventoryResult.
+var (
+	InventoryResult_name = map[int32]string{
+		0: "INVENTORY_RESULT_UNSPECIFIED",
+		1: "INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE",
+		2: "INVENTORY_RESULT_ALL_IN_STOCK_AT_SEVERAL",
+		3: "INVENTORY_RESULT_PARTIALLY_IN_STOCK",
+		4: "INVENTORY_RESULT_ALL_TO_PRODUCE",
+	}
+	InventoryResult_value = map[string]int32{
+		"INVENTORY_RESULT_UNSPECIFIED":             0,
+		"INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE":     1,
+		"INVENTORY_RESULT_ALL_IN_STOCK_AT_SEVERAL": 2,
+		"INVENTORY_RESULT_PARTIALLY_IN_STOCK":      3,
+		"INVENTORY_RESULT_ALL_TO_PRODUCE":          4,
+	}
+)
+
+func (x InventoryResult) Enum() *InventoryResult {
+	p := new(InventoryResult)
+	*p = x
+	return p
+}
+
+func (x InventoryResult) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InventoryResult) Descriptor() protoreflect.EnumDescriptor {
+	return file_stocks_v1_stocks_proto_enumTypes[1].Descriptor()
+}
+
+func (InventoryResult) Type() protoreflect.EnumType {
+	return &file_stocks_v1_stocks_proto_enumTypes[1]
+}
+
+func (x InventoryResult) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InventoryResult.Descriptor instead.
+func (InventoryResult) EnumDescriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{1}
+}
+
+type SkipReason int32
+
+const (
+	SkipReason_SKIP_REASON_UNSPECIFIED      SkipReason = 0
+	SkipReason_SKIP_REASON_IGNORED          SkipReason = 1
+	SkipReason_SKIP_REASON_REST_UNAVAILABLE SkipReason = 2
+	SkipReason_SKIP_REASON_PICKUP_ONLY      SkipReason = 3
+	SkipReason_SKIP_REASON_LEAD_TIME        SkipReason = 4
+)
+
+// Enum value maps for SkipReason.
+var (
+	SkipReason_name = map[int32]string{
+		0: "SKIP_REASON_UNSPECIFIED",
+		1: "SKIP_REASON_IGNORED",
+		2: "SKIP_REASON_REST_UNAVAILABLE",
+		3: "SKIP_REASON_PICKUP_ONLY",
+		4: "SKIP_REASON_LEAD_TIME",
+	}
+	SkipReason_value = map[string]int32{
+		"SKIP_REASON_UNSPECIFIED":      0,
+		"SKIP_REASON_IGNORED":          1,
+		"SKIP_REASON_REST_UNAVAILABLE": 2,
+		"SKIP_REASON_PICKUP_ONLY":      3,
+		"SKIP_REASON_LEAD_TIME":        4,
+	}
+)
+
+func (x SkipReason) Enum() *SkipReason {
+	p := new(SkipReason)
+	*p = x
+	return p
+}
+
+func (x SkipReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SkipReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_stocks_v1_stocks_proto_enumTypes[2].Descriptor()
+}
+
+func (SkipReason) Type() protoreflect.EnumType {
+	return &file_stocks_v1_stocks_proto_enumTypes[2]
+}
+
+func (x SkipReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SkipReason.Descriptor instead.
+func (SkipReason) EnumDescriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{2}
+}
+
+type SimpleProduct struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ProductId      string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity       string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	IsLocal        bool                   `protobuf:"varint,3,opt,name=is_local,json=isLocal,proto3" json:"is_local,omitempty"`
+	ChoicePriority ChoicePriority         `protobuf:"varint,4,opt,name=choice_priority,json=choicePriority,proto3,enum=stocks.v1.ChoicePriority" json:"choice_priority,omitempty"`
+	// Optional inside a composite product, where the composite's filial is used.
+	FilialId string `protobuf:"bytes,5,opt,name=filial_id,json=filialId,proto3" json:"filial_id,omitempty"`
+	// Warehouses this product must not be taken from.
+	IgnoredNodes []string `protobuf:"bytes,6,rep,name=ignored_nodes,json=ignoredNodes,proto3" json:"ignored_nodes,omitempty"`
+	// Longest acceptable lead time, only honoured by CHOICE_PRIORITY_CHEAPEST.
+	Deadline      *durationpb.Duration `protobuf:"bytes,7,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimpleProduct) Reset() {
+	*x = SimpleProduct{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimpleProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimpleProduct) ProtoMessage() {}
+
+func (x *SimpleProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimpleProduct.ProtoReflect.Descriptor instead.
+func (*SimpleProduct) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SimpleProduct) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SimpleProduct) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *SimpleProduct) GetIsLocal() bool {
+	if x != nil {
+		return x.IsLocal
+	}
+	return false
+}
+
+func (x *SimpleProduct) GetChoicePriority() ChoicePriority {
+	if x != nil {
+		return x.ChoicePriority
+	}
+	return ChoicePriority_CHOICE_PRIORITY_UNSPECIFIED
+}
+
+func (x *SimpleProduct) GetFilialId() string {
+	if x != nil {
+		return x.FilialId
+	}
+	return ""
+}
+
+func (x *SimpleProduct) GetIgnoredNodes() []string {
+	if x != nil {
+		return x.IgnoredNodes
+	}
+	return nil
+}
+
+func (x *SimpleProduct) GetDeadline() *durationpb.Duration {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+type CompositeProduct struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Products       []*SimpleProduct       `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	ChoicePriority ChoicePriority         `protobuf:"varint,2,opt,name=choice_priority,json=choicePriority,proto3,enum=stocks.v1.ChoicePriority" json:"choice_priority,omitempty"`
+	FilialId       string                 `protobuf:"bytes,3,opt,name=filial_id,json=filialId,proto3" json:"filial_id,omitempty"`
+	// Warehouses none of the products may be taken from.
+	IgnoredNodes []string `protobuf:"bytes,4,rep,name=ignored_nodes,json=ignoredNodes,proto3" json:"ignored_nodes,omitempty"`
+	// Applies to the products without a stricter deadline of their own.
+	Deadline      *durationpb.Duration `protobuf:"bytes,5,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompositeProduct) Reset() {
+	*x = CompositeProduct{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompositeProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompositeProduct) ProtoMessage() {}
+
+func (x *CompositeProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompositeProduct.ProtoReflect.Descriptor instead.
+func (*CompositeProduct) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompositeProduct) GetProducts() []*SimpleProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *CompositeProduct) GetChoicePriority() ChoicePriority {
+	if x != nil {
+		return x.ChoicePriority
+	}
+	return ChoicePriority_CHOICE_PRIORITY_UNSPECIFIED
+}
+
+func (x *CompositeProduct) GetFilialId() string {
+	if x != nil {
+		return x.FilialId
+	}
+	return ""
+}
+
+func (x *CompositeProduct) GetIgnoredNodes() []string {
+	if x != nil {
+		return x.IgnoredNodes
+	}
+	return nil
+}
+
+func (x *CompositeProduct) GetDeadline() *durationpb.Duration {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+type DeliveryItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Item:
+	//
+	//	*DeliveryItem_Simple
+	//	*DeliveryItem_Composite
+	Item          isDeliveryItem_Item `protobuf_oneof:"item"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryItem) Reset() {
+	*x = DeliveryItem{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryItem) ProtoMessage() {}
+
+func (x *DeliveryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryItem.ProtoReflect.Descriptor instead.
+func (*DeliveryItem) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DeliveryItem) GetItem() isDeliveryItem_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *DeliveryItem) GetSimple() *SimpleProduct {
+	if x != nil {
+		if x, ok := x.Item.(*DeliveryItem_Simple); ok {
+			return x.Simple
+		}
+	}
+	return nil
+}
+
+func (x *DeliveryItem) GetComposite() *CompositeProduct {
+	if x != nil {
+		if x, ok := x.Item.(*DeliveryItem_Composite); ok {
+			return x.Composite
+		}
+	}
+	return nil
+}
+
+type isDeliveryItem_Item interface {
+	isDeliveryItem_Item()
+}
+
+type DeliveryItem_Simple struct {
+	Simple *SimpleProduct `protobuf:"bytes,1,opt,name=simple,proto3,oneof"`
+}
+
+type DeliveryItem_Composite struct {
+	Composite *CompositeProduct `protobuf:"bytes,2,opt,name=composite,proto3,oneof"`
+}
+
+func (*DeliveryItem_Simple) isDeliveryItem_Item() {}
+
+func (*DeliveryItem_Composite) isDeliveryItem_Item() {}
+
+type InventoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*DeliveryItem        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Warehouse ids ordered from the nearest to the farthest.
+	Path []string `protobuf:"bytes,2,rep,name=path,proto3" json:"path,omitempty"`
+	// Pickup orders may also be served from pickup-only warehouses.
+	Pickup bool `protobuf:"varint,3,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	// Unit cost and lead time of path nodes, nodes without one cost nothing.
+	NodeCosts     []*NodeCost `protobuf:"bytes,4,rep,name=node_costs,json=nodeCosts,proto3" json:"node_costs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryRequest) Reset() {
+	*x = InventoryRequest{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryRequest) ProtoMessage() {}
+
+func (x *InventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryRequest.ProtoReflect.Descriptor instead.
+func (*InventoryRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InventoryRequest) GetItems() []*DeliveryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *InventoryRequest) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *InventoryRequest) GetPickup() bool {
+	if x != nil {
+		return x.Pickup
+	}
+	return false
+}
+
+func (x *InventoryRequest) GetNodeCosts() []*NodeCost {
+	if x != nil {
+		return x.NodeCosts
+	}
+	return nil
+}
+
+type NodeCost struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WarehouseId   string                 `protobuf:"bytes,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	Cost          string                 `protobuf:"bytes,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	LeadTime      *durationpb.Duration   `protobuf:"bytes,3,opt,name=lead_time,json=leadTime,proto3" json:"lead_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeCost) Reset() {
+	*x = NodeCost{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCost) ProtoMessage() {}
+
+func (x *NodeCost) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCost.ProtoReflect.Descriptor instead.
+func (*NodeCost) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NodeCost) GetWarehouseId() string {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return ""
+}
+
+func (x *NodeCost) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *NodeCost) GetLeadTime() *durationpb.Duration {
+	if x != nil {
+		return x.LeadTime
+	}
+	return nil
+}
+
+type StockState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	WarehouseId   *string                `protobuf:"bytes,3,opt,name=warehouse_id,json=warehouseId,proto3,oneof" json:"warehouse_id,omitempty"`
+	Produce       bool                   `protobuf:"varint,4,opt,name=produce,proto3" json:"produce,omitempty"`
+	Cost          string                 `protobuf:"bytes,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	LeadTime      *durationpb.Duration   `protobuf:"bytes,6,opt,name=lead_time,json=leadTime,proto3" json:"lead_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockState) Reset() {
+	*x = StockState{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockState) ProtoMessage() {}
+
+func (x *StockState) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockState.ProtoReflect.Descriptor instead.
+func (*StockState) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StockState) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockState) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *StockState) GetWarehouseId() string {
+	if x != nil && x.WarehouseId != nil {
+		return *x.WarehouseId
+	}
+	return ""
+}
+
+func (x *StockState) GetProduce() bool {
+	if x != nil {
+		return x.Produce
+	}
+	return false
+}
+
+func (x *StockState) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *StockState) GetLeadTime() *durationpb.Duration {
+	if x != nil {
+		return x.LeadTime
+	}
+	return nil
+}
+
+type SkippedNode struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WarehouseId string                 `protobuf:"bytes,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	// Not set when the node was skipped for every product.
+	ProductId     *string    `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3,oneof" json:"product_id,omitempty"`
+	Reason        SkipReason `protobuf:"varint,3,opt,name=reason,proto3,enum=stocks.v1.SkipReason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkippedNode) Reset() {
+	*x = SkippedNode{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkippedNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkippedNode) ProtoMessage() {}
+
+func (x *SkippedNode) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkippedNode.ProtoReflect.Descriptor instead.
+func (*SkippedNode) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SkippedNode) GetWarehouseId() string {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return ""
+}
+
+func (x *SkippedNode) GetProductId() string {
+	if x != nil && x.ProductId != nil {
+		return *x.ProductId
+	}
+	return ""
+}
+
+func (x *SkippedNode) GetReason() SkipReason {
+	if x != nil {
+		return x.Reason
+	}
+	return SkipReason_SKIP_REASON_UNSPECIFIED
+}
+
+type InventoryResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Result       InventoryResult        `protobuf:"varint,1,opt,name=result,proto3,enum=stocks.v1.InventoryResult" json:"result,omitempty"`
+	StockStates  []*StockState          `protobuf:"bytes,2,rep,name=stock_states,json=stockStates,proto3" json:"stock_states,omitempty"`
+	SkippedNodes []*SkippedNode         `protobuf:"bytes,3,rep,name=skipped_nodes,json=skippedNodes,proto3" json:"skipped_nodes,omitempty"`
+	// Total shipping cost and the longest lead time of the stock states.
+	Cost          string               `protobuf:"bytes,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	LeadTime      *durationpb.Duration `protobuf:"bytes,5,opt,name=lead_time,json=leadTime,proto3" json:"lead_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryResponse) Reset() {
+	*x = InventoryResponse{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryResponse) ProtoMessage() {}
+
+func (x *InventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryResponse.ProtoReflect.Descriptor instead.
+func (*InventoryResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InventoryResponse) GetResult() InventoryResult {
+	if x != nil {
+		return x.Result
+	}
+	return InventoryResult_INVENTORY_RESULT_UNSPECIFIED
+}
+
+func (x *InventoryResponse) GetStockStates() []*StockState {
+	if x != nil {
+		return x.StockStates
+	}
+	return nil
+}
+
+func (x *InventoryResponse) GetSkippedNodes() []*SkippedNode {
+	if x != nil {
+		return x.SkippedNodes
+	}
+	return nil
+}
+
+func (x *InventoryResponse) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *InventoryResponse) GetLeadTime() *durationpb.Duration {
+	if x != nil {
+		return x.LeadTime
+	}
+	return nil
+}
+
+type RequestedProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestedProduct) Reset() {
+	*x = RequestedProduct{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestedProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestedProduct) ProtoMessage() {}
+
+func (x *RequestedProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestedProduct.ProtoReflect.Descriptor instead.
+func (*RequestedProduct) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RequestedProduct) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RequestedProduct) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+type Rest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RestId        string                 `protobuf:"bytes,1,opt,name=rest_id,json=restId,proto3" json:"rest_id,omitempty"`
+	FilialId      *string                `protobuf:"bytes,2,opt,name=filial_id,json=filialId,proto3,oneof" json:"filial_id,omitempty"`
+	IntegrationId *string                `protobuf:"bytes,3,opt,name=integration_id,json=integrationId,proto3,oneof" json:"integration_id,omitempty"`
+	Quantity      string                 `protobuf:"bytes,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	ProductId     string                 `protobuf:"bytes,5,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	WarehouseId   string                 `protobuf:"bytes,6,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rest) Reset() {
+	*x = Rest{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rest) ProtoMessage() {}
+
+func (x *Rest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rest.ProtoReflect.Descriptor instead.
+func (*Rest) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Rest) GetRestId() string {
+	if x != nil {
+		return x.RestId
+	}
+	return ""
+}
+
+func (x *Rest) GetFilialId() string {
+	if x != nil && x.FilialId != nil {
+		return *x.FilialId
+	}
+	return ""
+}
+
+func (x *Rest) GetIntegrationId() string {
+	if x != nil && x.IntegrationId != nil {
+		return *x.IntegrationId
+	}
+	return ""
+}
+
+func (x *Rest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *Rest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Rest) GetWarehouseId() string {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return ""
+}
+
+type ProductInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Rest          *Rest                  `protobuf:"bytes,2,opt,name=rest,proto3" json:"rest,omitempty"`
+	Covered       bool                   `protobuf:"varint,3,opt,name=covered,proto3" json:"covered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductInfo) Reset() {
+	*x = ProductInfo{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductInfo) ProtoMessage() {}
+
+func (x *ProductInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductInfo.ProtoReflect.Descriptor instead.
+func (*ProductInfo) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProductInfo) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetRest() *Rest {
+	if x != nil {
+		return x.Rest
+	}
+	return nil
+}
+
+func (x *ProductInfo) GetCovered() bool {
+	if x != nil {
+		return x.Covered
+	}
+	return false
+}
+
+type GetStockOneItemInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *RequestedProduct      `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	FilialId      string                 `protobuf:"bytes,2,opt,name=filial_id,json=filialId,proto3" json:"filial_id,omitempty"`
+	ShipmentId    string                 `protobuf:"bytes,3,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockOneItemInfoRequest) Reset() {
+	*x = GetStockOneItemInfoRequest{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockOneItemInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockOneItemInfoRequest) ProtoMessage() {}
+
+func (x *GetStockOneItemInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockOneItemInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetStockOneItemInfoRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetStockOneItemInfoRequest) GetProduct() *RequestedProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *GetStockOneItemInfoRequest) GetFilialId() string {
+	if x != nil {
+		return x.FilialId
+	}
+	return ""
+}
+
+func (x *GetStockOneItemInfoRequest) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+type GetStockOneItemInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InStock       bool                   `protobuf:"varint,1,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	ProductInfo   *ProductInfo           `protobuf:"bytes,2,opt,name=product_info,json=productInfo,proto3" json:"product_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockOneItemInfoResponse) Reset() {
+	*x = GetStockOneItemInfoResponse{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockOneItemInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockOneItemInfoResponse) ProtoMessage() {}
+
+func (x *GetStockOneItemInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockOneItemInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetStockOneItemInfoResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetStockOneItemInfoResponse) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *GetStockOneItemInfoResponse) GetProductInfo() *ProductInfo {
+	if x != nil {
+		return x.ProductInfo
+	}
+	return nil
+}
+
+type GetStockManyItemsInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*RequestedProduct    `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	FilialId      string                 `protobuf:"bytes,2,opt,name=filial_id,json=filialId,proto3" json:"filial_id,omitempty"`
+	ShipmentId    string                 `protobuf:"bytes,3,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockManyItemsInfoRequest) Reset() {
+	*x = GetStockManyItemsInfoRequest{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockManyItemsInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockManyItemsInfoRequest) ProtoMessage() {}
+
+func (x *GetStockManyItemsInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockManyItemsInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetStockManyItemsInfoRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetStockManyItemsInfoRequest) GetProducts() []*RequestedProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *GetStockManyItemsInfoRequest) GetFilialId() string {
+	if x != nil {
+		return x.FilialId
+	}
+	return ""
+}
+
+func (x *GetStockManyItemsInfoRequest) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+type GetStockManyItemsInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InStock       bool                   `protobuf:"varint,1,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	ProductInfo   []*ProductInfo         `protobuf:"bytes,2,rep,name=product_info,json=productInfo,proto3" json:"product_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockManyItemsInfoResponse) Reset() {
+	*x = GetStockManyItemsInfoResponse{}
+	mi := &file_stocks_v1_stocks_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockManyItemsInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockManyItemsInfoResponse) ProtoMessage() {}
+
+func (x *GetStockManyItemsInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_v1_stocks_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockManyItemsInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetStockManyItemsInfoResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_v1_stocks_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetStockManyItemsInfoResponse) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *GetStockManyItemsInfoResponse) GetProductInfo() []*ProductInfo {
+	if x != nil {
+		return x.ProductInfo
+	}
+	return nil
+}
+
+var File_stocks_v1_stocks_proto protoreflect.FileDescriptor
+
+const file_stocks_v1_stocks_proto_rawDesc = "" +
+	"\n" +
+	"\x16stocks/v1/stocks.proto\x12\tstocks.v1\x1a\x1egoogle/protobuf/duration.proto\"\xa2\x02\n" +
+	"\rSimpleProduct\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12\x19\n" +
+	"\bis_local\x18\x03 \x01(\bR\aisLocal\x12B\n" +
+	"\x0fchoice_priority\x18\x04 \x01(\x0e2\x19.stocks.v1.ChoicePriorityR\x0echoicePriority\x12\x1b\n" +
+	"\tfilial_id\x18\x05 \x01(\tR\bfilialId\x12#\n" +
+	"\rignored_nodes\x18\x06 \x03(\tR\fignoredNodes\x125\n" +
+	"\bdeadline\x18\a \x01(\v2\x19.google.protobuf.DurationR\bdeadline\"\x85\x02\n" +
+	"\x10CompositeProduct\x124\n" +
+	"\bproducts\x18\x01 \x03(\v2\x18.stocks.v1.SimpleProductR\bproducts\x12B\n" +
+	"\x0fchoice_priority\x18\x02 \x01(\x0e2\x19.stocks.v1.ChoicePriorityR\x0echoicePriority\x12\x1b\n" +
+	"\tfilial_id\x18\x03 \x01(\tR\bfilialId\x12#\n" +
+	"\rignored_nodes\x18\x04 \x03(\tR\fignoredNodes\x125\n" +
+	"\bdeadline\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bdeadline\"\x87\x01\n" +
+	"\fDeliveryItem\x122\n" +
+	"\x06simple\x18\x01 \x01(\v2\x18.stocks.v1.SimpleProductH\x00R\x06simple\x12;\n" +
+	"\tcomposite\x18\x02 \x01(\v2\x1b.stocks.v1.CompositeProductH\x00R\tcompositeB\x06\n" +
+	"\x04item\"\xa1\x01\n" +
+	"\x10InventoryRequest\x12-\n" +
+	"\x05items\x18\x01 \x03(\v2\x17.stocks.v1.DeliveryItemR\x05items\x12\x12\n" +
+	"\x04path\x18\x02 \x03(\tR\x04path\x12\x16\n" +
+	"\x06pickup\x18\x03 \x01(\bR\x06pickup\x122\n" +
+	"\n" +
+	"node_costs\x18\x04 \x03(\v2\x13.stocks.v1.NodeCostR\tnodeCosts\"y\n" +
+	"\bNodeCost\x12!\n" +
+	"\fwarehouse_id\x18\x01 \x01(\tR\vwarehouseId\x12\x12\n" +
+	"\x04cost\x18\x02 \x01(\tR\x04cost\x126\n" +
+	"\tlead_time\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bleadTime\"\xe6\x01\n" +
+	"\n" +
+	"StockState\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12&\n" +
+	"\fwarehouse_id\x18\x03 \x01(\tH\x00R\vwarehouseId\x88\x01\x01\x12\x18\n" +
+	"\aproduce\x18\x04 \x01(\bR\aproduce\x12\x12\n" +
+	"\x04cost\x18\x05 \x01(\tR\x04cost\x126\n" +
+	"\tlead_time\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\bleadTimeB\x0f\n" +
+	"\r_warehouse_id\"\x92\x01\n" +
+	"\vSkippedNode\x12!\n" +
+	"\fwarehouse_id\x18\x01 \x01(\tR\vwarehouseId\x12\"\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tH\x00R\tproductId\x88\x01\x01\x12-\n" +
+	"\x06reason\x18\x03 \x01(\x0e2\x15.stocks.v1.SkipReasonR\x06reasonB\r\n" +
+	"\v_product_id\"\x8a\x02\n" +
+	"\x11InventoryResponse\x122\n" +
+	"\x06result\x18\x01 \x01(\x0e2\x1a.stocks.v1.InventoryResultR\x06result\x128\n" +
+	"\fstock_states\x18\x02 \x03(\v2\x15.stocks.v1.StockStateR\vstockStates\x12;\n" +
+	"\rskipped_nodes\x18\x03 \x03(\v2\x16.stocks.v1.SkippedNodeR\fskippedNodes\x12\x12\n" +
+	"\x04cost\x18\x04 \x01(\tR\x04cost\x126\n" +
+	"\tlead_time\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bleadTime\"M\n" +
+	"\x10RequestedProduct\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\"\xec\x01\n" +
+	"\x04Rest\x12\x17\n" +
+	"\arest_id\x18\x01 \x01(\tR\x06restId\x12 \n" +
+	"\tfilial_id\x18\x02 \x01(\tH\x00R\bfilialId\x88\x01\x01\x12*\n" +
+	"\x0eintegration_id\x18\x03 \x01(\tH\x01R\rintegrationId\x88\x01\x01\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\tR\bquantity\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x05 \x01(\tR\tproductId\x12!\n" +
+	"\fwarehouse_id\x18\x06 \x01(\tR\vwarehouseIdB\f\n" +
+	"\n" +
+	"_filial_idB\x11\n" +
+	"\x0f_integration_id\"k\n" +
+	"\vProductInfo\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12#\n" +
+	"\x04rest\x18\x02 \x01(\v2\x0f.stocks.v1.RestR\x04rest\x12\x18\n" +
+	"\acovered\x18\x03 \x01(\bR\acovered\"\x91\x01\n" +
+	"\x1aGetStockOneItemInfoRequest\x125\n" +
+	"\aproduct\x18\x01 \x01(\v2\x1b.stocks.v1.RequestedProductR\aproduct\x12\x1b\n" +
+	"\tfilial_id\x18\x02 \x01(\tR\bfilialId\x12\x1f\n" +
+	"\vshipment_id\x18\x03 \x01(\tR\n" +
+	"shipmentId\"s\n" +
+	"\x1bGetStockOneItemInfoResponse\x12\x19\n" +
+	"\bin_stock\x18\x01 \x01(\bR\ainStock\x129\n" +
+	"\fproduct_info\x18\x02 \x01(\v2\x16.stocks.v1.ProductInfoR\vproductInfo\"\x95\x01\n" +
+	"\x1cGetStockManyItemsInfoRequest\x127\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1b.stocks.v1.RequestedProductR\bproducts\x12\x1b\n" +
+	"\tfilial_id\x18\x02 \x01(\tR\bfilialId\x12\x1f\n" +
+	"\vshipment_id\x18\x03 \x01(\tR\n" +
+	"shipmentId\"u\n" +
+	"\x1dGetStockManyItemsInfoResponse\x12\x19\n" +
+	"\bin_stock\x18\x01 \x01(\bR\ainStock\x129\n" +
+	"\fproduct_info\x18\x02 \x03(\v2\x16.stocks.v1.ProductInfoR\vproductInfo*\xb1\x01\n" +
+	"\x0eChoicePriority\x12\x1f\n" +
+	"\x1bCHOICE_PRIORITY_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17CHOICE_PRIORITY_NEAREST\x10\x01\x12\x1c\n" +
+	"\x18CHOICE_PRIORITY_FARTHEST\x10\x02\x12%\n" +
+	"!CHOICE_PRIORITY_FEWEST_WAREHOUSES\x10\x03\x12\x1c\n" +
+	"\x18CHOICE_PRIORITY_CHEAPEST\x10\x04*\xd9\x01\n" +
+	"\x0fInventoryResult\x12 \n" +
+	"\x1cINVENTORY_RESULT_UNSPECIFIED\x10\x00\x12(\n" +
+	"$INVENTORY_RESULT_ALL_IN_STOCK_AT_ONE\x10\x01\x12,\n" +
+	"(INVENTORY_RESULT_ALL_IN_STOCK_AT_SEVERAL\x10\x02\x12'\n" +
+	"#INVENTORY_RESULT_PARTIALLY_IN_STOCK\x10\x03\x12#\n" +
+	"\x1fINVENTORY_RESULT_ALL_TO_PRODUCE\x10\x04*\x9c\x01\n" +
+	"\n" +
+	"SkipReason\x12\x1b\n" +
+	"\x17SKIP_REASON_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13SKIP_REASON_IGNORED\x10\x01\x12 \n" +
+	"\x1cSKIP_REASON_REST_UNAVAILABLE\x10\x02\x12\x1b\n" +
+	"\x17SKIP_REASON_PICKUP_ONLY\x10\x03\x12\x19\n" +
+	"\x15SKIP_REASON_LEAD_TIME\x10\x042Z\n" +
+	"\x10InventoryService\x12F\n" +
+	"\tInventory\x12\x1b.stocks.v1.InventoryRequest\x1a\x1c.stocks.v1.InventoryResponse2\xe3\x01\n" +
+	"\x0fRestInfoService\x12d\n" +
+	"\x13GetStockOneItemInfo\x12%.stocks.v1.GetStockOneItemInfoRequest\x1a&.stocks.v1.GetStockOneItemInfoResponse\x12j\n" +
+	"\x15GetStockManyItemsInfo\x12'.stocks.v1.GetStockManyItemsInfoRequest\x1a(.stocks.v1.GetStockManyItemsInfoResponseB7Z5github.com/DimKa163/stocks/pkg/api/stocks/v1;stocksv1b\x06proto3"
+
+var (
+	file_stocks_v1_stocks_proto_rawDescOnce sync.Once
+	file_stocks_v1_stocks_proto_rawDescData []byte
+)
+
+func file_stocks_v1_stocks_proto_rawDescGZIP() []byte {
+	file_stocks_v1_stocks_proto_rawDescOnce.Do(func() {
+		file_stocks_v1_stocks_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stocks_v1_stocks_proto_rawDesc), len(file_stocks_v1_stocks_proto_rawDesc)))
+	})
+	return file_stocks_v1_stocks_proto_rawDescData
+}
+
+var file_stocks_v1_stocks_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_stocks_v1_stocks_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_stocks_v1_stocks_proto_goTypes = []any{
+	(ChoicePriority)(0),                   // 0: stocks.v1.ChoicePriority
+	(InventoryResult)(0),                  // 1: stocks.v1.InventoryResult
+	(SkipReason)(0),                       // 2: stocks.v1.SkipReason
+	(*SimpleProduct)(nil),                 // 3: stocks.v1.SimpleProduct
+	(*CompositeProduct)(nil),              // 4: stocks.v1.CompositeProduct
+	(*DeliveryItem)(nil),                  // 5: stocks.v1.DeliveryItem
+	(*InventoryRequest)(nil),              // 6: stocks.v1.InventoryRequest
+	(*NodeCost)(nil),                      // 7: stocks.v1.NodeCost
+	(*StockState)(nil),                    // 8: stocks.v1.StockState
+	(*SkippedNode)(nil),                   // 9: stocks.v1.SkippedNode
+	(*InventoryResponse)(nil),             // 10: stocks.v1.InventoryResponse
+	(*RequestedProduct)(nil),              // 11: stocks.v1.RequestedProduct
+	(*Rest)(nil),                          // 12: stocks.v1.Rest
+	(*ProductInfo)(nil),                   // 13: stocks.v1.ProductInfo
+	(*GetStockOneItemInfoRequest)(nil),    // 14: stocks.v1.GetStockOneItemInfoRequest
+	(*GetStockOneItemInfoResponse)(nil),   // 15: stocks.v1.GetStockOneItemInfoResponse
+	(*GetStockManyItemsInfoRequest)(nil),  // 16: stocks.v1.GetStockManyItemsInfoRequest
+	(*GetStockManyItemsInfoResponse)(nil), // 17: stocks.v1.GetStockManyItemsInfoResponse
+	(*durationpb.Duration)(nil),           // 18: google.protobuf.Duration
+}
+var file_stocks_v1_stocks_proto_depIdxs = []int32{
+	0,  // 0: stocks.v1.SimpleProduct.choice_priority:type_name -> stocks.v1.ChoicePriority
+	18, // 1: stocks.v1.SimpleProduct.deadline:type_name -> google.protobuf.Duration
+	3,  // 2: stocks.v1.CompositeProduct.products:type_name -> stocks.v1.SimpleProduct
+	0,  // 3: stocks.v1.CompositeProduct.choice_priority:type_name -> stocks.v1.ChoicePriority
+	18, // 4: stocks.v1.CompositeProduct.deadline:type_name -> google.protobuf.Duration
+	3,  // 5: stocks.v1.DeliveryItem.simple:type_name -> stocks.v1.SimpleProduct
+	4,  // 6: stocks.v1.DeliveryItem.composite:type_name -> stocks.v1.CompositeProduct
+	5,  // 7: stocks.v1.InventoryRequest.items:type_name -> stocks.v1.DeliveryItem
+	7,  // 8: stocks.v1.InventoryRequest.node_costs:type_name -> stocks.v1.NodeCost
+	18, // 9: stocks.v1.NodeCost.lead_time:type_name -> google.protobuf.Duration
+	18, // 10: stocks.v1.StockState.lead_time:type_name -> google.protobuf.Duration
+	2,  // 11: stocks.v1.SkippedNode.reason:type_name -> stocks.v1.SkipReason
+	1,  // 12: stocks.v1.InventoryResponse.result:type_name -> stocks.v1.InventoryResult
+	8,  // 13: stocks.v1.InventoryResponse.stock_states:type_name -> stocks.v1.StockState
+	9,  // 14: stocks.v1.InventoryResponse.skipped_nodes:type_name -> stocks.v1.SkippedNode
+	18, // 15: stocks.v1.InventoryResponse.lead_time:type_name -> google.protobuf.Duration
+	12, // 16: stocks.v1.ProductInfo.rest:type_name -> stocks.v1.Rest
+	11, // 17: stocks.v1.GetStockOneItemInfoRequest.product:type_name -> stocks.v1.RequestedProduct
+	13, // 18: stocks.v1.GetStockOneItemInfoResponse.product_info:type_name -> stocks.v1.ProductInfo
+	11, // 19: stocks.v1.GetStockManyItemsInfoRequest.products:type_name -> stocks.v1.RequestedProduct
+	13, // 20: stocks.v1.GetStockManyItemsInfoResponse.product_info:type_name -> stocks.v1.ProductInfo
+	6,  // 21: stocks.v1.InventoryService.Inventory:input_type -> stocks.v1.InventoryRequest
+	14, // 22: stocks.v1.RestInfoService.GetStockOneItemInfo:input_type -> stocks.v1.GetStockOneItemInfoRequest
+	16, // 23: stocks.v1.RestInfoService.GetStockManyItemsInfo:input_type -> stocks.v1.GetStockManyItemsInfoRequest
+	10, // 24: stocks.v1.InventoryService.Inventory:output_type -> stocks.v1.InventoryResponse
+	15, // 25: stocks.v1.RestInfoService.GetStockOneItemInfo:output_type -> stocks.v1.GetStockOneItemInfoResponse
+	17, // 26: stocks.v1.RestInfoService.GetStockManyItemsInfo:output_type -> stocks.v1.GetStockManyItemsInfoResponse
+	24, // [24:27] is the sub-list for method output_type
+	21, // [21:24] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_stocks_v1_stocks_proto_init() }
+func file_stocks_v1_stocks_proto_init() {
+	if File_stocks_v1_stocks_proto != nil {
+		return
+	}
+	file_stocks_v1_stocks_proto_msgTypes[2].OneofWrappers = []any{
+		(*DeliveryItem_Simple)(nil),
+		(*DeliveryItem_Composite)(nil),
+	}
+	file_stocks_v1_stocks_proto_msgTypes[5].OneofWrappers = []any{}
+	file_stocks_v1_stocks_proto_msgTypes[6].OneofWrappers = []any{}
+	file_stocks_v1_stocks_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stocks_v1_stocks_proto_rawDesc), len(file_stocks_v1_stocks_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_stocks_v1_stocks_proto_goTypes,
+		DependencyIndexes: file_stocks_v1_stocks_proto_depIdxs,
+		EnumInfos:         file_stocks_v1_stocks_proto_enumTypes,
+		MessageInfos:      file_stocks_v1_stocks_proto_msgTypes,
+	}.Build()
+	File_stocks_v1_stocks_proto = out.File
+	file_stocks_v1_stocks_proto_goTypes = nil
+	file_stocks_v1_stocks_proto_depIdxs = nil
+}
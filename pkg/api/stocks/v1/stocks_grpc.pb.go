@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: stocks/v1/stocks.proto
+
+package stocksv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InventoryService_Inventory_FullMethodName = "/stocks.v1.InventoryService/Inventory"
+)
+
+// InventoryServiceClient is the client API for InventoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryServiceClient interface {
+	Inventory(ctx context.Context, in *InventoryRequest, opts ...grpc.CallOption) (*InventoryResponse, error)
+}
+
+type inventoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryServiceClient(cc grpc.ClientConnInterface) InventoryServiceClient {
+	return &inventoryServiceClient{cc}
+}
+
+func (c *inventoryServiceClient) Inventory(ctx context.Context, in *InventoryRequest, opts ...grpc.CallOption) (*InventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InventoryResponse)
+	err := c.cc.Invoke(ctx, InventoryService_Inventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryServiceServer is the server API for InventoryService service.
+// All implementations must embed UnimplementedInventoryServiceServer
+// for forward compatibility.
+type InventoryServiceServer interface {
+	Inventory(context.Context, *InventoryRequest) (*InventoryResponse, error)
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+// UnimplementedInventoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInventoryServiceServer struct{}
+
+func (UnimplementedInventoryServiceServer) Inventory(context.Context, *InventoryRequest) (*InventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inventory not implemented")
+}
+func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
+func (UnimplementedInventoryServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServiceServer will
+// result in compilation errors.
+type UnsafeInventoryServiceServer interface {
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+func RegisterInventoryServiceServer(s grpc.ServiceRegistrar, srv InventoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInventoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InventoryService_ServiceDesc, srv)
+}
+
+func _InventoryService_Inventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).Inventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_Inventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).Inventory(ctx, req.(*InventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stocks.v1.InventoryService",
+	HandlerType: (*InventoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Inventory",
+			Handler:    _InventoryService_Inventory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "stocks/v1/stocks.proto",
+}
+
+const (
+	RestInfoService_GetStockOneItemInfo_FullMethodName   = "/stocks.v1.RestInfoService/GetStockOneItemInfo"
+	RestInfoService_GetStockManyItemsInfo_FullMethodName = "/stocks.v1.RestInfoService/GetStockManyItemsInfo"
+)
+
+// RestInfoServiceClient is the client API for RestInfoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RestInfoServiceClient interface {
+	GetStockOneItemInfo(ctx context.Context, in *GetStockOneItemInfoRequest, opts ...grpc.CallOption) (*GetStockOneItemInfoResponse, error)
+	GetStockManyItemsInfo(ctx context.Context, in *GetStockManyItemsInfoRequest, opts ...grpc.CallOption) (*GetStockManyItemsInfoResponse, error)
+}
+
+type restInfoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRestInfoServiceClient(cc grpc.ClientConnInterface) RestInfoServiceClient {
+	return &restInfoServiceClient{cc}
+}
+
+func (c *restInfoServiceClient) GetStockOneItemInfo(ctx context.Context, in *GetStockOneItemInfoRequest, opts ...grpc.CallOption) (*GetStockOneItemInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStockOneItemInfoResponse)
+	err := c.cc.Invoke(ctx, RestInfoService_GetStockOneItemInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *restInfoServiceClient) GetStockManyItemsInfo(ctx context.Context, in *GetStockManyItemsInfoRequest, opts ...grpc.CallOption) (*GetStockManyItemsInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStockManyItemsInfoResponse)
+	err := c.cc.Invoke(ctx, RestInfoService_GetStockManyItemsInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestInfoServiceServer is the server API for RestInfoService service.
+// All implementations must embed UnimplementedRestInfoServiceServer
+// for forward compatibility.
+type RestInfoServiceServer interface {
+	GetStockOneItemInfo(context.Context, *GetStockOneItemInfoRequest) (*GetStockOneItemInfoResponse, error)
+	GetStockManyItemsInfo(context.Context, *GetStockManyItemsInfoRequest) (*GetStockManyItemsInfoResponse, error)
+	mustEmbedUnimplementedRestInfoServiceServer()
+}
+
+// UnimplementedRestInfoServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRestInfoServiceServer struct{}
+
+func (UnimplementedRestInfoServiceServer) GetStockOneItemInfo(context.Context, *GetStockOneItemInfoRequest) (*GetStockOneItemInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockOneItemInfo not implemented")
+}
+func (UnimplementedRestInfoServiceServer) GetStockManyItemsInfo(context.Context, *GetStockManyItemsInfoRequest) (*GetStockManyItemsInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockManyItemsInfo not implemented")
+}
+func (UnimplementedRestInfoServiceServer) mustEmbedUnimplementedRestInfoServiceServer() {}
+func (UnimplementedRestInfoServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeRestInfoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RestInfoServiceServer will
+// result in compilation errors.
+type UnsafeRestInfoServiceServer interface {
+	mustEmbedUnimplementedRestInfoServiceServer()
+}
+
+func RegisterRestInfoServiceServer(s grpc.ServiceRegistrar, srv RestInfoServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRestInfoServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RestInfoService_ServiceDesc, srv)
+}
+
+func _RestInfoService_GetStockOneItemInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockOneItemInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RestInfoServiceServer).GetStockOneItemInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RestInfoService_GetStockOneItemInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RestInfoServiceServer).GetStockOneItemInfo(ctx, req.(*GetStockOneItemInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RestInfoService_GetStockManyItemsInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockManyItemsInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RestInfoServiceServer).GetStockManyItemsInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RestInfoService_GetStockManyItemsInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RestInfoServiceServer).GetStockManyItemsInfo(ctx, req.(*GetStockManyItemsInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RestInfoService_ServiceDesc is the grpc.ServiceDesc for RestInfoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RestInfoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stocks.v1.RestInfoService",
+	HandlerType: (*RestInfoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStockOneItemInfo",
+			Handler:    _RestInfoService_GetStockOneItemInfo_Handler,
+		},
+		{
+			MethodName: "GetStockManyItemsInfo",
+			Handler:    _RestInfoService_GetStockManyItemsInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "stocks/v1/stocks.proto",
+}